@@ -0,0 +1,144 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// dataSourceHostSchema returns the computed attributes of a host, shared by
+// the purestorage_host and purestorage_hosts data sources.
+func dataSourceHostSchema() map[string]*schema.Schema {
+	stringList := func(description string) *schema.Schema {
+		return &schema.Schema{
+			Type:        schema.TypeList,
+			Description: description,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+			Computed: true,
+		}
+	}
+
+	return map[string]*schema.Schema{
+		"wwn":             stringList("List of Fibre Channel worldwide names (WWNs) of the host."),
+		"iqn":             stringList("List of iSCSI qualified names (IQNs) of the host."),
+		"nqn":             stringList("List of NVMeF qualified names (NQNs) of the host."),
+		"preferred_array": stringList("List of preferred arrays."),
+		"personality": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"hgroup": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"volume": &schema.Schema{
+			Type:        schema.TypeList,
+			Description: "Private volume connections of the host.",
+			Computed:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"vol": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"lun": {
+						Type:     schema.TypeInt,
+						Computed: true,
+					},
+				},
+			},
+		},
+		"shared_volume": &schema.Schema{
+			Type:        schema.TypeList,
+			Description: "Volume connections of the host through its hostgroup.",
+			Computed:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"vol": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"lun": {
+						Type:     schema.TypeInt,
+						Computed: true,
+					},
+					"hgroup": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourcePureHost() *schema.Resource {
+	s := dataSourceHostSchema()
+	s["name"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "Name of the host.",
+		Required:    true,
+	}
+
+	return &schema.Resource{
+		Read:   dataSourcePureHostRead,
+		Schema: s,
+	}
+}
+
+func dataSourcePureHostRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	host, err := client.Hosts.GetHost(d.Get("name").(string), nil)
+	if err != nil {
+		return err
+	}
+
+	personality, err := client.Hosts.GetHost(host.Name, map[string]string{"personality": "true"})
+	if err != nil {
+		return err
+	}
+
+	preferred, err := client.Hosts.GetHost(host.Name, map[string]string{"preferred_array": "true"})
+	if err != nil {
+		return err
+	}
+
+	connections, err := client.Hosts.ListHostConnections(host.Name, nil)
+	if err != nil {
+		return err
+	}
+	private, shared := flattenHostConnections(connections)
+
+	d.SetId(host.Name)
+	d.Set("wwn", host.Wwn)
+	d.Set("iqn", host.Iqn)
+	d.Set("nqn", host.Nqn)
+	d.Set("hgroup", host.Hgroup)
+	d.Set("personality", personality.Personality)
+	d.Set("preferred_array", preferred.PreferredArray)
+	if err := d.Set("volume", private); err != nil {
+		return err
+	}
+	if err := d.Set("shared_volume", shared); err != nil {
+		return err
+	}
+	return nil
+}
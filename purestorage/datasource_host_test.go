@@ -0,0 +1,93 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourcePureHost_basic(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureHostDataSourceConfig(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.purestorage_host.tfhosttest", "wwn.0", "0000999900009999"),
+					resource.TestCheckResourceAttr("data.purestorage_host.tfhosttest", "hgroup", fmt.Sprintf("tfhosttest-hgroup-%d", rInt)),
+					resource.TestCheckResourceAttr("data.purestorage_host.tfhosttest", "volume.#", "1"),
+					resource.TestCheckResourceAttr("data.purestorage_host.tfhosttest", "volume.0.lun", "10"),
+					resource.TestCheckResourceAttr("data.purestorage_host.tfhosttest", "shared_volume.#", "1"),
+					resource.TestCheckResourceAttr("data.purestorage_hosts.tfhosttest", "names.#", "1"),
+					resource.TestCheckResourceAttr("data.purestorage_hosts.tfhosttest", "hosts.0.shared_volume.#", "1"),
+					resource.TestCheckResourceAttr("data.purestorage_hostgroup.tfhosttest", "hosts.#", "1"),
+					resource.TestCheckResourceAttr("data.purestorage_hostgroup.tfhosttest", "volume.0.lun", "20"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckPureHostDataSourceConfig(rInt int) string {
+	return fmt.Sprintf(`
+resource "purestorage_volume" "tfhosttest-private" {
+	name = "tfhosttest-private-%d"
+	size = 1024000000
+}
+
+resource "purestorage_volume" "tfhosttest-shared" {
+	name = "tfhosttest-shared-%d"
+	size = 1024000000
+}
+
+resource "purestorage_hostgroup" "tfhosttest" {
+	name = "tfhosttest-hgroup-%d"
+	volume {
+		vol = "${purestorage_volume.tfhosttest-shared.name}"
+		lun = 20
+	}
+}
+
+resource "purestorage_host" "tfhosttest" {
+	name   = "tfhosttest%d"
+	wwn    = ["0000999900009999"]
+	hgroup = "${purestorage_hostgroup.tfhosttest.name}"
+	volume {
+		vol = "${purestorage_volume.tfhosttest-private.name}"
+		lun = 10
+	}
+}
+
+data "purestorage_host" "tfhosttest" {
+	name = "${purestorage_host.tfhosttest.name}"
+}
+
+data "purestorage_hosts" "tfhosttest" {
+	hgroup = "${purestorage_host.tfhosttest.hgroup}"
+}
+
+data "purestorage_hostgroup" "tfhosttest" {
+	name = "${purestorage_host.tfhosttest.hgroup}"
+}`, rInt, rInt, rInt, rInt)
+}
@@ -0,0 +1,82 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourcePureHostgroup() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourcePureHostgroupRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Name of the hostgroup.",
+				Required:    true,
+			},
+			"hosts": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: "List of member hosts.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
+			"volume": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: "Shared volume connections of the hostgroup.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"vol": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"lun": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourcePureHostgroupRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	h, err := client.Hostgroups.GetHostgroup(d.Get("name").(string), nil)
+	if err != nil {
+		return err
+	}
+
+	volumes, err := client.Hostgroups.ListHostgroupConnections(h.Name)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(h.Name)
+	d.Set("hosts", h.Hosts)
+	if err := d.Set("volume", flattenHgroupVolume(volumes)); err != nil {
+		return err
+	}
+	return nil
+}
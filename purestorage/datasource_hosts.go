@@ -0,0 +1,148 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourcePureHosts() *schema.Resource {
+	host := dataSourceHostSchema()
+	host["name"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+
+	return &schema.Resource{
+		Read: dataSourcePureHostsRead,
+
+		Schema: map[string]*schema.Schema{
+			"name_glob": &schema.Schema{
+				Type:         schema.TypeString,
+				Description:  "Only return hosts with names matching this glob pattern, such as esx-*.",
+				Optional:     true,
+				ValidateFunc: validateGlob,
+			},
+			"hgroup": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Only return hosts that are members of this hostgroup.",
+				Optional:    true,
+			},
+			"names": &schema.Schema{
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
+			"hosts": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: host,
+				},
+			},
+		},
+	}
+}
+
+// dataSourcePureHostsRead lists the hosts.  Personalities, preferred arrays
+// and connections are listed for all hosts at once, rather than host by
+// host.
+func dataSourcePureHostsRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	hosts, err := client.Hosts.ListHosts(nil)
+	if err != nil {
+		return err
+	}
+
+	personalities, err := client.Hosts.ListHosts(map[string]string{"personality": "true"})
+	if err != nil {
+		return err
+	}
+	personality := make(map[string]string)
+	for _, h := range personalities {
+		personality[h.Name] = h.Personality
+	}
+
+	preferredArrays, err := client.Hosts.ListHosts(map[string]string{"preferred_array": "true"})
+	if err != nil {
+		return err
+	}
+	preferredArray := make(map[string][]string)
+	for _, h := range preferredArrays {
+		preferredArray[h.Name] = h.PreferredArray
+	}
+
+	req, err := client.NewRequest("GET", "host", map[string]string{"connect": "true"}, nil)
+	if err != nil {
+		return err
+	}
+	var connected []flasharray.ConnectedVolume
+	if _, err = client.Do(req, &connected, false); err != nil {
+		return err
+	}
+	connections := make(map[string][]flasharray.ConnectedVolume)
+	for _, c := range connected {
+		connections[c.Name] = append(connections[c.Name], c)
+	}
+
+	glob := d.Get("name_glob").(string)
+	hgroup := d.Get("hgroup").(string)
+
+	var names []string
+	var out []map[string]interface{}
+	for _, h := range hosts {
+		if glob != "" {
+			if matched, _ := path.Match(glob, h.Name); !matched {
+				continue
+			}
+		}
+		if hgroup != "" && h.Hgroup != hgroup {
+			continue
+		}
+
+		private, shared := flattenHostConnections(connections[h.Name])
+
+		names = append(names, h.Name)
+		out = append(out, map[string]interface{}{
+			"name":            h.Name,
+			"wwn":             h.Wwn,
+			"iqn":             h.Iqn,
+			"nqn":             h.Nqn,
+			"hgroup":          h.Hgroup,
+			"personality":     personality[h.Name],
+			"preferred_array": preferredArray[h.Name],
+			"volume":          private,
+			"shared_volume":   shared,
+		})
+	}
+
+	d.SetId(strconv.Itoa(hashcode.String(strings.Join(names, ","))))
+	d.Set("names", names)
+	if err := d.Set("hosts", out); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,367 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// pgroupSnapshot is a protection group snapshot with the progress of its
+// transfer from the source array.  The transfer fields are only returned
+// for replicated snapshots.
+type pgroupSnapshot struct {
+	Name            string   `json:"name"`
+	Source          string   `json:"source"`
+	Created         string   `json:"created"`
+	Started         string   `json:"started,omitempty"`
+	Completed       string   `json:"completed,omitempty"`
+	Progress        *float64 `json:"progress,omitempty"`
+	DataTransferred *int     `json:"data_transferred,omitempty"`
+}
+
+func dataSourcePureProtectiongroup() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourcePureProtectiongroupRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Name of the protection group.",
+				Required:    true,
+			},
+			"suffix_glob": &schema.Schema{
+				Type:         schema.TypeString,
+				Description:  "Only return snapshots with suffixes matching this glob pattern, such as daily-*.",
+				Optional:     true,
+				ValidateFunc: validateGlob,
+			},
+			"max_age": &schema.Schema{
+				Type:         schema.TypeString,
+				Description:  "Only return snapshots created within this duration, such as 7d.",
+				Optional:     true,
+				ValidateFunc: validateDuration,
+			},
+			"hosts": &schema.Schema{
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
+			"volumes": &schema.Schema{
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
+			"hgroups": &schema.Schema{
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
+			"source": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"target": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"allowed": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"snap_enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"snap_frequency": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"snap_at": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"replicate_enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"replicate_frequency": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"replicate_at": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"blackout": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"start": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"end": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"all_for": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"days": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"per_day": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"target_all_for": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"target_days": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"target_per_day": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"snapshots": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: "Snapshots of the protection group, oldest first.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"suffix": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"created": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"started": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"completed": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"progress": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"data_transferred": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"latest": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Name of the newest snapshot that is fully transferred.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourcePureProtectiongroupRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	name := d.Get("name").(string)
+
+	p, err := client.Protectiongroups.GetProtectiongroup(name, nil)
+	if err != nil {
+		return err
+	}
+
+	s, err := getPgroupSchedule(client, name)
+	if err != nil {
+		return err
+	}
+	blackouts, err := s.blackouts()
+	if err != nil {
+		return err
+	}
+
+	r, err := client.Protectiongroups.GetProtectiongroup(name, map[string]string{"retention": "true"})
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := getPgroupSnapshots(client, name)
+	if err != nil {
+		return err
+	}
+
+	var maxAge int
+	if v, ok := d.GetOk("max_age"); ok {
+		maxAge, _ = parseDuration(v.(string))
+	}
+	snapshots, err = filterPgroupSnapshots(snapshots, d.Get("suffix_glob").(string), maxAge, time.Now())
+	if err != nil {
+		return err
+	}
+
+	d.SetId(p.Name)
+	d.Set("hosts", p.Hosts)
+	d.Set("volumes", p.Volumes)
+	d.Set("hgroups", p.Hgroups)
+	d.Set("source", p.Source)
+	if err := d.Set("target", flattenPgroupTargets(p.Targets)); err != nil {
+		return err
+	}
+
+	d.Set("snap_enabled", s.SnapEnabled)
+	d.Set("snap_frequency", strconv.Itoa(s.SnapFrequency))
+	d.Set("snap_at", strconv.Itoa(s.SnapAt))
+	d.Set("replicate_enabled", s.ReplicateEnabled)
+	d.Set("replicate_frequency", strconv.Itoa(s.ReplicateFrequency))
+	d.Set("replicate_at", strconv.Itoa(s.ReplicateAt))
+	if err := d.Set("blackout", flattenPgroupBlackouts(blackouts)); err != nil {
+		return err
+	}
+
+	d.Set("all_for", strconv.Itoa(r.Allfor))
+	d.Set("days", r.Days)
+	d.Set("per_day", r.Perday)
+	d.Set("target_all_for", strconv.Itoa(r.TargetAllfor))
+	d.Set("target_days", r.TargetDays)
+	d.Set("target_per_day", r.TargetPerDay)
+
+	if err := d.Set("snapshots", flattenPgroupSnapshots(snapshots)); err != nil {
+		return err
+	}
+	d.Set("latest", latestPgroupSnapshot(snapshots))
+	return nil
+}
+
+// getPgroupSnapshots returns the snapshots of a protection group, with the
+// transfer progress of the snapshots that were replicated from another
+// array.
+func getPgroupSnapshots(client *flasharray.Client, name string) ([]pgroupSnapshot, error) {
+	req, err := client.NewRequest("GET", fmt.Sprintf("pgroup/%s", name), map[string]string{"snap": "true"}, nil)
+	if err != nil {
+		return nil, err
+	}
+	snapshots := []pgroupSnapshot{}
+	if _, err = client.Do(req, &snapshots, false); err != nil {
+		return nil, err
+	}
+
+	req, err = client.NewRequest("GET", fmt.Sprintf("pgroup/%s", name), map[string]string{"snap": "true", "transfer": "true"}, nil)
+	if err != nil {
+		return nil, err
+	}
+	transfers := []pgroupSnapshot{}
+	if _, err = client.Do(req, &transfers, false); err != nil {
+		return nil, err
+	}
+
+	for i := range snapshots {
+		for _, t := range transfers {
+			if t.Name == snapshots[i].Name {
+				snapshots[i].Started = t.Started
+				snapshots[i].Completed = t.Completed
+				snapshots[i].Progress = t.Progress
+				snapshots[i].DataTransferred = t.DataTransferred
+			}
+		}
+	}
+	return snapshots, nil
+}
+
+// filterPgroupSnapshots returns the snapshots with a suffix matching the
+// glob, created no more than maxAge seconds before now, oldest first.  An
+// empty glob or a maxAge of 0 does not filter.
+func filterPgroupSnapshots(snapshots []pgroupSnapshot, glob string, maxAge int, now time.Time) ([]pgroupSnapshot, error) {
+	var out []pgroupSnapshot
+	for _, s := range snapshots {
+		if glob != "" {
+			_, suffix, _ := splitSnapshotName(s.Name)
+			if matched, _ := path.Match(glob, suffix); !matched {
+				continue
+			}
+		}
+
+		if maxAge > 0 {
+			created, err := time.Parse(time.RFC3339, s.Created)
+			if err != nil {
+				return nil, fmt.Errorf("invalid created time %q of snapshot %s", s.Created, s.Name)
+			}
+			if now.Sub(created) > time.Duration(maxAge)*time.Second {
+				continue
+			}
+		}
+
+		out = append(out, s)
+	}
+
+	// The created times are all RFC 3339 in UTC, so they sort as strings
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Created < out[j].Created })
+	return out, nil
+}
+
+// latestPgroupSnapshot returns the name of the newest snapshot.  A
+// replicated snapshot that is still being transferred is not a recovery
+// point, so it is skipped.
+func latestPgroupSnapshot(snapshots []pgroupSnapshot) string {
+	var latest pgroupSnapshot
+	for _, s := range snapshots {
+		if s.Started != "" && s.Completed == "" {
+			continue
+		}
+		if latest.Name == "" || s.Created >= latest.Created {
+			latest = s
+		}
+	}
+	return latest.Name
+}
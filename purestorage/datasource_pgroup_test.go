@@ -0,0 +1,126 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourcePureProtectiongroup_basic(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureProtectiongroupDataSourceConfig(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.purestorage_protectiongroup.tfpgrouptest", "volumes.#", "1"),
+					resource.TestCheckResourceAttr("data.purestorage_protectiongroup.tfpgrouptest", "snap_frequency", "14400"),
+					resource.TestCheckResourceAttr("data.purestorage_protectiongroup.tfpgrouptest", "all_for", "604800"),
+					resource.TestCheckResourceAttr("data.purestorage_protectiongroup.tfpgrouptest", "snapshots.#", "1"),
+					resource.TestCheckResourceAttr("data.purestorage_protectiongroup.tfpgrouptest", "snapshots.0.suffix", "daily-1"),
+					resource.TestCheckResourceAttr("data.purestorage_protectiongroup.tfpgrouptest", "latest", fmt.Sprintf("tfpgrouptest-%d.daily-1", rInt)),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckPureProtectiongroupDataSourceConfig(rInt int) string {
+	return fmt.Sprintf(`
+resource "purestorage_volume" "tfpgrouptest" {
+	name = "tfpgrouptest-%d"
+	size = 1024000000
+}
+
+resource "purestorage_protectiongroup" "tfpgrouptest" {
+	name           = "tfpgrouptest-%d"
+	volumes        = ["${purestorage_volume.tfpgrouptest.name}"]
+	snap_frequency = "4h"
+	all_for        = "7d"
+	destroy_mode   = "eradicate"
+}
+
+resource "purestorage_protectiongroup_snapshot" "daily" {
+	protection_group = "${purestorage_protectiongroup.tfpgrouptest.name}"
+	suffix           = "daily-1"
+	destroy_mode     = "eradicate"
+}
+
+resource "purestorage_protectiongroup_snapshot" "manual" {
+	protection_group = "${purestorage_protectiongroup.tfpgrouptest.name}"
+	suffix           = "manual-1"
+	destroy_mode     = "eradicate"
+}
+
+data "purestorage_protectiongroup" "tfpgrouptest" {
+	name        = "${purestorage_protectiongroup.tfpgrouptest.name}"
+	suffix_glob = "daily-*"
+	max_age     = "1d"
+	depends_on  = ["purestorage_protectiongroup_snapshot.daily", "purestorage_protectiongroup_snapshot.manual"]
+}`, rInt, rInt)
+}
+
+func Test_filterPgroupSnapshots(t *testing.T) {
+	now := time.Date(2019, 6, 8, 12, 0, 0, 0, time.UTC)
+	snapshots := []pgroupSnapshot{
+		{Name: "pg.daily-2", Created: "2019-06-08T00:00:00Z"},
+		{Name: "pg.manual", Created: "2019-06-08T06:00:00Z"},
+		{Name: "pg.daily-1", Created: "2019-06-01T12:00:00Z"},
+	}
+
+	filtered, err := filterPgroupSnapshots(snapshots, "daily-*", 0, now)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(filtered) != 2 || filtered[0].Name != "pg.daily-1" || filtered[1].Name != "pg.daily-2" {
+		t.Fatalf("Wrong snapshots returned: %v", filtered)
+	}
+
+	filtered, err = filterPgroupSnapshots(snapshots, "", 86400, now)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(filtered) != 2 || filtered[0].Name != "pg.daily-2" {
+		t.Fatalf("Wrong snapshots returned: %v", filtered)
+	}
+
+	if _, err := filterPgroupSnapshots([]pgroupSnapshot{{Name: "pg.1", Created: "yesterday"}}, "", 86400, now); err == nil {
+		t.Fatal("Expected error for invalid created time")
+	}
+}
+
+func Test_latestPgroupSnapshot(t *testing.T) {
+	snapshots := []pgroupSnapshot{
+		{Name: "array1:pg.1", Created: "2019-06-01T12:00:00Z", Started: "2019-06-01T12:00:01Z", Completed: "2019-06-01T12:05:00Z"},
+		{Name: "array1:pg.2", Created: "2019-06-02T12:00:00Z", Started: "2019-06-02T12:00:01Z", Completed: "2019-06-02T12:05:00Z"},
+		{Name: "array1:pg.3", Created: "2019-06-03T12:00:00Z", Started: "2019-06-03T12:00:01Z"},
+	}
+	if latest := latestPgroupSnapshot(snapshots); latest != "array1:pg.2" {
+		t.Fatalf("Wrong snapshot returned: %s", latest)
+	}
+	if latest := latestPgroupSnapshot(nil); latest != "" {
+		t.Fatalf("Wrong snapshot returned: %s", latest)
+	}
+}
@@ -0,0 +1,105 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"fmt"
+
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourcePureVolume() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourcePureVolumeRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Name of the volume, including its pod or volume group. Either name or serial must be provided.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"serial": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Serial of the volume. Either name or serial must be provided.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"size": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"source": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"created": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"pod": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"volume_group": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourcePureVolumeRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	var vol *flasharray.Volume
+	var err error
+
+	name := d.Get("name").(string)
+	serial := d.Get("serial").(string)
+
+	switch {
+	case name != "" && serial != "":
+		return fmt.Errorf("only one of name or serial can be provided")
+	case name != "":
+		if vol, err = client.Volumes.GetVolume(name, nil); err != nil {
+			return err
+		}
+	case serial != "":
+		if vol, err = getVolumeBySerial(client, serial); err != nil {
+			return err
+		}
+		if vol == nil {
+			return fmt.Errorf("no volume found with serial %s", serial)
+		}
+	default:
+		return fmt.Errorf("one of name or serial must be provided")
+	}
+
+	pod, vg, _ := splitVolumeName(vol.Name)
+
+	d.SetId(vol.Name)
+	d.Set("name", vol.Name)
+	d.Set("serial", vol.Serial)
+	d.Set("size", vol.Size)
+	d.Set("source", vol.Source)
+	d.Set("created", vol.Created)
+	d.Set("pod", pod)
+	d.Set("volume_group", vg)
+	return nil
+}
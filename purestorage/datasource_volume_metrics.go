@@ -0,0 +1,167 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+// volumePerformanceSchema is the schema of a volume performance sample
+// returned by MonitorVolume.
+func volumePerformanceSchema() map[string]*schema.Schema {
+	s := make(map[string]*schema.Schema)
+	for _, k := range []string{
+		"reads_per_sec", "writes_per_sec", "input_per_sec", "output_per_sec",
+		"usec_per_read_op", "usec_per_write_op", "san_usec_per_read_op", "san_usec_per_write_op",
+		"bytes_per_read", "bytes_per_write", "bytes_per_op",
+	} {
+		s[k] = &schema.Schema{
+			Type:     schema.TypeInt,
+			Computed: true,
+		}
+	}
+	s["time"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+	return s
+}
+
+func dataSourcePureVolumeMetrics() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourcePureVolumeMetricsRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Name of the volume.",
+				Required:    true,
+			},
+			"historical": &schema.Schema{
+				Type:         schema.TypeString,
+				Description:  "Window of historical performance to return. If not provided, only the current performance is returned.",
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"1h", "3h", "24h", "7d", "30d", "90d", "1y"}, false),
+			},
+			"size": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"volumes": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "Physical space used by the volume data, in bytes.",
+				Computed:    true,
+			},
+			"snapshots": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "Physical space used by the volume snapshots, in bytes.",
+				Computed:    true,
+			},
+			"shared_space": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"system": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"total": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "Total physical space used by the volume, in bytes.",
+				Computed:    true,
+			},
+			"data_reduction": &schema.Schema{
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"thin_provisioning": &schema.Schema{
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"total_reduction": &schema.Schema{
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"performance": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: "The current performance of the volume, or the latest sample if historical is set.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: volumePerformanceSchema(),
+				},
+			},
+			"history": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: "Performance samples over the historical window.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: volumePerformanceSchema(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourcePureVolumeMetricsRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	name := d.Get("name").(string)
+
+	space, err := client.Volumes.GetVolume(name, map[string]string{"space": "true"})
+	if err != nil {
+		return err
+	}
+
+	params := map[string]string{"size": "true"}
+	if historical, ok := d.GetOk("historical"); ok {
+		params["historical"] = historical.(string)
+	}
+	samples, err := client.Volumes.MonitorVolume(name, params)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(space.Name)
+	d.Set("size", space.Size)
+	d.Set("volumes", intValue(space.Volumes))
+	d.Set("snapshots", intValue(space.Snapshots))
+	d.Set("shared_space", intValue(space.SharedSpace))
+	d.Set("system", intValue(space.System))
+	d.Set("total", intValue(space.Total))
+	d.Set("data_reduction", floatValue(space.DataReduction))
+	d.Set("thin_provisioning", floatValue(space.ThinProvisioning))
+	d.Set("total_reduction", floatValue(space.TotalReduction))
+
+	history := flattenVolumePerformance(samples)
+	var performance []map[string]interface{}
+	if len(history) > 0 {
+		performance = history[len(history)-1:]
+	}
+	if err := d.Set("performance", performance); err != nil {
+		return err
+	}
+
+	if _, ok := d.GetOk("historical"); !ok {
+		history = nil
+	}
+	if err := d.Set("history", history); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourcePureVolume_basic(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureVolumeDataSourceConfig(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.purestorage_volume.byname", "size", "1024000000"),
+					resource.TestCheckResourceAttrPair("data.purestorage_volume.byname", "serial", "purestorage_volume.tfvolumetest", "serial"),
+					resource.TestCheckResourceAttr("data.purestorage_volume.byserial", "name", fmt.Sprintf("tfvolumetest-%d", rInt)),
+					resource.TestCheckResourceAttr("data.purestorage_volumes.glob", "names.#", "1"),
+					resource.TestCheckResourceAttr("data.purestorage_volumes.glob", "volumes.0.size", "1024000000"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckPureVolumeDataSourceConfig(rInt int) string {
+	return fmt.Sprintf(`
+resource "purestorage_volume" "tfvolumetest" {
+	name = "tfvolumetest-%d"
+	size = 1024000000
+}
+
+data "purestorage_volume" "byname" {
+	name = "${purestorage_volume.tfvolumetest.name}"
+}
+
+data "purestorage_volume" "byserial" {
+	serial = "${purestorage_volume.tfvolumetest.serial}"
+}
+
+data "purestorage_volumes" "glob" {
+	name_glob = "${purestorage_volume.tfvolumetest.name}*"
+}`, rInt)
+}
@@ -0,0 +1,186 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourcePureVolumes() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourcePureVolumesRead,
+
+		Schema: map[string]*schema.Schema{
+			"name_glob": &schema.Schema{
+				Type:         schema.TypeString,
+				Description:  "Only return volumes with names matching this glob pattern, such as app-*.",
+				Optional:     true,
+				ValidateFunc: validateGlob,
+			},
+			"protection_group": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Only return volumes that are members of this protection group.",
+				Optional:    true,
+			},
+			"host": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Only return volumes connected to this host.",
+				Optional:    true,
+			},
+			"hostgroup": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Only return volumes connected to this hostgroup.",
+				Optional:    true,
+			},
+			"pending_eradication": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: "Return only destroyed volumes that are pending eradication, instead of only volumes that are not destroyed.",
+				Optional:    true,
+				Default:     false,
+			},
+			"names": &schema.Schema{
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
+			"volumes": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"size": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"serial": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"source": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"created": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourcePureVolumesRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	var params map[string]string
+	if d.Get("pending_eradication").(bool) {
+		params = map[string]string{"pending_only": "true"}
+	}
+
+	volumes, err := client.Volumes.ListVolumes(params)
+	if err != nil {
+		return err
+	}
+
+	// Each filter that is set limits the volumes to its list of names.
+	var filters [][]string
+
+	if pg, ok := d.GetOk("protection_group"); ok {
+		pgroup, err := client.Protectiongroups.GetProtectiongroup(pg.(string), nil)
+		if err != nil {
+			return err
+		}
+		filters = append(filters, pgroup.Volumes)
+	}
+
+	if h, ok := d.GetOk("host"); ok {
+		connections, err := client.Hosts.ListHostConnections(h.(string), nil)
+		if err != nil {
+			return err
+		}
+		var names []string
+		for _, c := range connections {
+			names = append(names, c.Vol)
+		}
+		filters = append(filters, names)
+	}
+
+	if hg, ok := d.GetOk("hostgroup"); ok {
+		connections, err := client.Hostgroups.ListHostgroupConnections(hg.(string))
+		if err != nil {
+			return err
+		}
+		var names []string
+		for _, c := range connections {
+			names = append(names, c.Vol)
+		}
+		filters = append(filters, names)
+	}
+
+	glob := d.Get("name_glob").(string)
+
+	var names []string
+	var out []map[string]interface{}
+	for _, v := range volumes {
+		if glob != "" {
+			if matched, _ := path.Match(glob, v.Name); !matched {
+				continue
+			}
+		}
+
+		matched := true
+		for _, f := range filters {
+			if !stringInSlice(v.Name, f) {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		names = append(names, v.Name)
+		out = append(out, map[string]interface{}{
+			"name":    v.Name,
+			"size":    v.Size,
+			"serial":  v.Serial,
+			"source":  v.Source,
+			"created": v.Created,
+		})
+	}
+
+	d.SetId(strconv.Itoa(hashcode.String(strings.Join(names, ","))))
+	d.Set("names", names)
+	if err := d.Set("volumes", out); err != nil {
+		return err
+	}
+	return nil
+}
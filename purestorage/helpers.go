@@ -16,6 +16,78 @@
 
 package purestorage
 
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+// Valid values of destroy_mode
+const (
+	destroyModeDestroy   = "destroy"
+	destroyModeEradicate = "eradicate"
+)
+
+// schemaDestroyMode returns the destroy_mode argument shared by resources
+// that can be eradicated after they are destroyed.
+func schemaDestroyMode() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Description:  "Whether to only destroy the object, leaving it pending eradication for 24 hours, or to eradicate it immediately.",
+		Optional:     true,
+		Default:      destroyModeDestroy,
+		ValidateFunc: validation.StringInSlice([]string{destroyModeDestroy, destroyModeEradicate}, false),
+	}
+}
+
+// schemaRecoverIfDestroyed returns the recover_if_destroyed argument shared
+// by resources that can be recovered from the destroyed bin.
+func schemaRecoverIfDestroyed() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Description: "Recover a destroyed object with the same name, instead of failing to create a new one.",
+		Optional:    true,
+		Default:     false,
+	}
+}
+
+// isPendingEradication reports whether the named object of the given type,
+// such as volume or pgroup, is destroyed and pending eradication.  The
+// pending objects are listed without a name filter and searched, since the
+// array returns an error when no object matches the name, which cannot be
+// told apart from a failed request.
+func isPendingEradication(client *flasharray.Client, path string, name string) (bool, error) {
+	req, err := client.NewRequest("GET", path, map[string]string{"pending_only": "true"}, nil)
+	if err != nil {
+		return false, err
+	}
+	var objects []struct {
+		Name string `json:"name"`
+	}
+	if _, err = client.Do(req, &objects, false); err != nil {
+		return false, err
+	}
+
+	for _, o := range objects {
+		if o.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // Return values in slice1 that are not in slice2
 func difference(slice1 []string, slice2 []string) []string {
 	var diff []string
@@ -72,3 +144,453 @@ func stringInSlice(a string, list []string) bool {
 	}
 	return false
 }
+
+// validateGlob checks that a glob pattern is well formed.  path.Match only
+// reports a malformed pattern when it is matched, so a filter with a bad
+// pattern would otherwise silently match nothing.
+func validateGlob(v interface{}, k string) ([]string, []error) {
+	if _, err := path.Match(v.(string), ""); err != nil {
+		return nil, []error{fmt.Errorf("%s: invalid glob pattern %q: %s", k, v.(string), err)}
+	}
+	return nil, nil
+}
+
+// Multipliers of the size units accepted by Purity
+var sizeUnits = map[string]int{
+	"":  1,
+	"K": 1 << 10,
+	"M": 1 << 20,
+	"G": 1 << 30,
+	"T": 1 << 40,
+	"P": 1 << 50,
+}
+
+// parseVolumeSize returns the number of bytes in a size such as "500G".
+// A plain integer is a size in bytes.
+func parseVolumeSize(size string) (int, error) {
+	s := strings.ToUpper(strings.TrimSpace(size))
+	s = strings.TrimSuffix(s, "B")
+
+	unit := ""
+	if len(s) > 0 {
+		if _, ok := sizeUnits[s[len(s)-1:]]; ok {
+			unit = s[len(s)-1:]
+			s = s[:len(s)-1]
+		}
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q, expected a number of bytes or a number followed by K, M, G, T or P", size)
+	}
+	if n > math.MaxInt64/sizeUnits[unit] {
+		return 0, fmt.Errorf("invalid size %q, the size is too large", size)
+	}
+	return n * sizeUnits[unit], nil
+}
+
+// validateVolumeSize checks that a volume size can be parsed and is a
+// multiple of 512 bytes.
+func validateVolumeSize(v interface{}, k string) ([]string, []error) {
+	size, err := parseVolumeSize(v.(string))
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s: %s", k, err)}
+	}
+	if size%512 != 0 {
+		return nil, []error{fmt.Errorf("%s: size %q must be a multiple of 512 bytes", k, v.(string))}
+	}
+	return nil, nil
+}
+
+// normalizeVolumeSize is a StateFunc that stores a volume size in bytes,
+// so it matches the size reported by the array.
+func normalizeVolumeSize(v interface{}) string {
+	size, err := parseVolumeSize(v.(string))
+	if err != nil {
+		return v.(string)
+	}
+	return strconv.Itoa(size)
+}
+
+// Seconds in each of the duration units accepted for schedules and retention
+var durationUnits = map[string]int{
+	"s": 1,
+	"m": 60,
+	"h": 60 * 60,
+	"d": 24 * 60 * 60,
+	"w": 7 * 24 * 60 * 60,
+}
+
+var durationRegexp = regexp.MustCompile(`^(\d+[smhdw])+$`)
+var durationPartRegexp = regexp.MustCompile(`(\d+)([smhdw])`)
+
+// parseDuration returns the number of seconds in a duration such as "4h",
+// "7d" or "1h30m".  A plain integer is a number of seconds.
+func parseDuration(duration string) (int, error) {
+	s := strings.ToLower(strings.TrimSpace(duration))
+
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 {
+		return n, nil
+	}
+	if !durationRegexp.MatchString(s) {
+		return 0, fmt.Errorf("invalid duration %q, expected a number of seconds or a duration such as 4h or 7d", duration)
+	}
+
+	seconds := 0
+	for _, part := range durationPartRegexp.FindAllStringSubmatch(s, -1) {
+		n, _ := strconv.Atoi(part[1])
+		seconds += n * durationUnits[part[2]]
+	}
+	return seconds, nil
+}
+
+// validateDuration checks that a duration can be parsed.
+func validateDuration(v interface{}, k string) ([]string, []error) {
+	if _, err := parseDuration(v.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%s: %s", k, err)}
+	}
+	return nil, nil
+}
+
+// normalizeDuration is a StateFunc that stores a duration in seconds, so it
+// matches the value reported by the array.
+func normalizeDuration(v interface{}) string {
+	seconds, err := parseDuration(v.(string))
+	if err != nil {
+		return v.(string)
+	}
+	return strconv.Itoa(seconds)
+}
+
+var timeOfDayRegexp = regexp.MustCompile(`^(\d{1,2}):(\d{2})$`)
+
+// parseTimeOfDay returns the number of seconds after midnight of a time
+// such as "02:00".  A plain integer is a number of seconds after midnight.
+// Schedules run on the hour, so the time must be a whole hour.
+func parseTimeOfDay(tod string) (int, error) {
+	s := strings.TrimSpace(tod)
+
+	seconds, err := strconv.Atoi(s)
+	if err != nil {
+		m := timeOfDayRegexp.FindStringSubmatch(s)
+		if m == nil {
+			return 0, fmt.Errorf("invalid time of day %q, expected a time such as 02:00", tod)
+		}
+		hours, _ := strconv.Atoi(m[1])
+		minutes, _ := strconv.Atoi(m[2])
+		if minutes > 59 {
+			return 0, fmt.Errorf("invalid time of day %q, expected a time such as 02:00", tod)
+		}
+		seconds = hours*3600 + minutes*60
+	}
+
+	if seconds < 0 || seconds >= 24*3600 {
+		return 0, fmt.Errorf("time of day %q must be between 00:00 and 23:00", tod)
+	}
+	if seconds%3600 != 0 {
+		return 0, fmt.Errorf("time of day %q must be on the hour", tod)
+	}
+	return seconds, nil
+}
+
+// validateTimeOfDay checks that a time of day can be parsed.
+func validateTimeOfDay(v interface{}, k string) ([]string, []error) {
+	if _, err := parseTimeOfDay(v.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%s: %s", k, err)}
+	}
+	return nil, nil
+}
+
+// normalizeTimeOfDay is a StateFunc that stores a time of day in seconds
+// after midnight, so it matches the value reported by the array.
+func normalizeTimeOfDay(v interface{}) string {
+	seconds, err := parseTimeOfDay(v.(string))
+	if err != nil {
+		return v.(string)
+	}
+	return strconv.Itoa(seconds)
+}
+
+// suppressUnsetTimeOfDay suppresses the diff between an unset time of day
+// and midnight.  The array reports an unset time as 0, which is the same as
+// 00:00.
+func suppressUnsetTimeOfDay(k, old, new string, d *schema.ResourceData) bool {
+	if old == "" {
+		old = "0"
+	}
+	if new == "" {
+		new = "0"
+	}
+	return normalizeTimeOfDay(old) == normalizeTimeOfDay(new)
+}
+
+// restVersionAtLeast reports whether the REST API version of the session is
+// at least the given major.minor version.
+func restVersionAtLeast(version string, minimum string) bool {
+	v := strings.SplitN(version, ".", 2)
+	min := strings.SplitN(minimum, ".", 2)
+	if len(v) != 2 || len(min) != 2 {
+		return false
+	}
+
+	vMajor, _ := strconv.Atoi(v[0])
+	vMinor, _ := strconv.Atoi(v[1])
+	minMajor, _ := strconv.Atoi(min[0])
+	minMinor, _ := strconv.Atoi(min[1])
+
+	if vMajor != minMajor {
+		return vMajor > minMajor
+	}
+	return vMinor >= minMinor
+}
+
+// intValue returns the value of an optional integer returned by the array,
+// or 0 if it was not returned.
+func intValue(i *int) int {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
+
+// floatValue returns the value of an optional float returned by the array,
+// or 0 if it was not returned.
+func floatValue(f *float64) float64 {
+	if f == nil {
+		return 0
+	}
+	return *f
+}
+
+// splitConnectionID splits the ID of a connection or membership resource,
+// such as host/vol, into its two names.  Host and host group names cannot
+// contain a slash, so only the first one is used; the second name may be a
+// volume in a volume group.
+func splitConnectionID(id string) (string, string, error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid ID %q, expected two names separated by a slash", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+// schemaVolumeConnections returns the volume set shared by hosts and
+// hostgroups.  Elements are hashed by volume name only, so a LUN chosen by
+// the array does not cause a diff and a LUN change is an in-place update.
+func schemaVolumeConnections(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Description: description,
+		Optional:    true,
+		Set:         resourceVolumeConnectionHash,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"vol": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"lun": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Computed:     true,
+					ValidateFunc: validation.IntBetween(0, 16383),
+				},
+			},
+		},
+	}
+}
+
+func resourceVolumeConnectionHash(v interface{}) int {
+	return hashcode.String(v.(map[string]interface{})["vol"].(string))
+}
+
+// volumeConnection is a volume to connect to a host or hostgroup, and the
+// data of the connect request.
+type volumeConnection struct {
+	vol  string
+	data map[string]interface{}
+}
+
+// volumeConnectionChanges returns the volumes to disconnect and the
+// connections to make for a change to the volume set.  A volume whose LUN
+// changed is in both, as it must be disconnected before it is reconnected.
+// A connection only includes a lun if one was configured.
+func volumeConnectionChanges(d *schema.ResourceData) ([]string, []volumeConnection) {
+	o, n := d.GetChange("volume")
+	os := o.(*schema.Set)
+	ns := n.(*schema.Set)
+
+	oldLuns := make(map[string]int)
+	for _, v := range os.List() {
+		vol := v.(map[string]interface{})
+		oldLuns[vol["vol"].(string)] = vol["lun"].(int)
+	}
+
+	var disconnect []string
+	var connect []volumeConnection
+	for _, v := range ns.List() {
+		vol := v.(map[string]interface{})
+		name := vol["vol"].(string)
+		lun := vol["lun"].(int)
+
+		oldLun, connected := oldLuns[name]
+		delete(oldLuns, name)
+		if connected && oldLun == lun {
+			continue
+		}
+
+		data := make(map[string]interface{})
+		if connected {
+			disconnect = append(disconnect, name)
+			data["lun"] = lun
+		} else if _, ok := d.GetOkExists(fmt.Sprintf("volume.%d.lun", ns.F(v))); ok {
+			data["lun"] = lun
+		}
+		connect = append(connect, volumeConnection{vol: name, data: data})
+	}
+
+	for name := range oldLuns {
+		disconnect = append(disconnect, name)
+	}
+	return disconnect, connect
+}
+
+// checkVolumeConnectionLuns returns an error if a LUN to be connected is
+// used by a connection that is not being disconnected, or is requested for
+// more than one volume.
+func checkVolumeConnectionLuns(connected []flasharray.ConnectedVolume, disconnect []string, connect []volumeConnection) error {
+	used := make(map[int]string)
+	for _, c := range connected {
+		if !stringInSlice(c.Vol, disconnect) {
+			used[c.Lun] = c.Vol
+		}
+	}
+
+	for _, c := range connect {
+		lun, ok := c.data["lun"]
+		if !ok {
+			continue
+		}
+		if vol, ok := used[lun.(int)]; ok && vol != c.vol {
+			return fmt.Errorf("LUN %d is already used by volume %s", lun.(int), vol)
+		}
+		used[lun.(int)] = c.vol
+	}
+	return nil
+}
+
+var (
+	wwnRegexp = regexp.MustCompile(`^[0-9A-Fa-f]{16}$`)
+	iqnRegexp = regexp.MustCompile(`(?i)^iqn\.\d{4}-\d{2}\.[a-z0-9]([a-z0-9.-]*[a-z0-9])?(:.+)?$`)
+	euiRegexp = regexp.MustCompile(`(?i)^eui\.[0-9a-f]{16}$`)
+	naaRegexp = regexp.MustCompile(`(?i)^naa\.([0-9a-f]{16}|[0-9a-f]{32})$`)
+	nqnRegexp = regexp.MustCompile(`^nqn\.\d{4}-\d{2}\.[^:]+:.+$`)
+)
+
+// normalizeWWN returns a WWN in the format returned by the array, sixteen
+// upper case hex digits without separators.
+func normalizeWWN(wwn string) string {
+	return strings.ToUpper(strings.Replace(strings.TrimSpace(wwn), ":", "", -1))
+}
+
+// validateWWN checks that a WWN is sixteen hex digits, optionally
+// separated by colons.
+func validateWWN(v interface{}, k string) ([]string, []error) {
+	if !wwnRegexp.MatchString(normalizeWWN(v.(string))) {
+		return nil, []error{fmt.Errorf("%s: %q is not a valid WWN, expected 16 hex digits such as 10:00:00:00:C9:12:34:56", k, v.(string))}
+	}
+	return nil, nil
+}
+
+// normalizeIQN returns an iSCSI name in the format returned by the array.
+// iqn names are lower case, eui and naa names have upper case hex digits.
+func normalizeIQN(iqn string) string {
+	iqn = strings.TrimSpace(iqn)
+	if euiRegexp.MatchString(iqn) || naaRegexp.MatchString(iqn) {
+		return strings.ToLower(iqn[:4]) + strings.ToUpper(iqn[4:])
+	}
+	return strings.ToLower(iqn)
+}
+
+// validateIQN checks that an iSCSI name is in the iqn, eui or naa format.
+func validateIQN(v interface{}, k string) ([]string, []error) {
+	iqn := strings.TrimSpace(v.(string))
+	if !iqnRegexp.MatchString(iqn) && !euiRegexp.MatchString(iqn) && !naaRegexp.MatchString(iqn) {
+		return nil, []error{fmt.Errorf("%s: %q is not a valid iSCSI name, expected an iqn., eui. or naa. name", k, v.(string))}
+	}
+	return nil, nil
+}
+
+// normalizeNQN returns an NVMe qualified name in the format returned by the
+// array.  NQNs are case sensitive, so only surrounding space is removed.
+func normalizeNQN(nqn string) string {
+	return strings.TrimSpace(nqn)
+}
+
+// validateNQN checks that an NVMe qualified name is in the nqn format and
+// no longer than 223 bytes.
+func validateNQN(v interface{}, k string) ([]string, []error) {
+	nqn := normalizeNQN(v.(string))
+	if len(nqn) > 223 || !nqnRegexp.MatchString(nqn) {
+		return nil, []error{fmt.Errorf("%s: %q is not a valid NQN, expected a name such as nqn.2014-08.org.nvmexpress:uuid:<uuid>", k, v.(string))}
+	}
+	return nil, nil
+}
+
+// schemaInitiators returns a set of host initiator names.  Names are
+// validated, and normalized before they are hashed and stored, so a name
+// written in a different format than the array returns does not cause a
+// diff.
+func schemaInitiators(description string, normalize func(string) string, validate schema.SchemaValidateFunc) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Description: description,
+		Optional:    true,
+		Elem: &schema.Schema{
+			Type:         schema.TypeString,
+			ValidateFunc: validate,
+			StateFunc: func(v interface{}) string {
+				return normalize(v.(string))
+			},
+		},
+		Set: func(v interface{}) int {
+			return hashcode.String(normalize(v.(string)))
+		},
+	}
+}
+
+// expandInitiators returns the normalized names in a set of initiators.
+func expandInitiators(s *schema.Set, normalize func(string) string) []string {
+	names := []string{}
+	for _, v := range s.List() {
+		names = append(names, normalize(v.(string)))
+	}
+	return names
+}
+
+// hashSecret is a StateFunc that stores the SHA-256 hash of a secret
+// instead of the secret itself.
+func hashSecret(v interface{}) string {
+	secret := v.(string)
+	if secret == "" {
+		return ""
+	}
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+const chapSecretCharacters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// generateChapSecret returns a random 32 character CHAP secret.
+func generateChapSecret() (string, error) {
+	secret := make([]byte, 32)
+	max := big.NewInt(int64(len(chapSecretCharacters)))
+	for i := range secret {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		secret[i] = chapSecretCharacters[n.Int64()]
+	}
+	return string(secret), nil
+}
@@ -18,6 +18,8 @@ package purestorage
 
 import (
 	"testing"
+
+	"github.com/devans10/pugo/flasharray"
 )
 
 func Test_difference(t *testing.T) {
@@ -55,3 +57,286 @@ func Test_stringInSlice(t *testing.T) {
 		t.Fatal("Returned false")
 	}
 }
+
+func Test_validateGlob(t *testing.T) {
+	for _, glob := range []string{"", "app-*", "daily-[0-9]*", "host?"} {
+		if _, errs := validateGlob(glob, "name_glob"); len(errs) > 0 {
+			t.Fatalf("Unexpected errors for %s: %s", glob, errs)
+		}
+	}
+	for _, glob := range []string{"daily-[", "app-\\"} {
+		if _, errs := validateGlob(glob, "name_glob"); len(errs) == 0 {
+			t.Fatalf("Expected error for %s", glob)
+		}
+	}
+}
+
+func Test_parseVolumeSize(t *testing.T) {
+	sizes := map[string]int{
+		"1024000000": 1024000000,
+		"512M":       536870912,
+		"500G":       536870912000,
+		"2t":         2199023255552,
+		"1GB":        1073741824,
+	}
+	for in, expected := range sizes {
+		size, err := parseVolumeSize(in)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if size != expected {
+			t.Fatalf("Wrong value returned for %s: %d", in, size)
+		}
+	}
+
+	for _, in := range []string{"", "G", "1.5G", "-1G", "10X", "99999999999P"} {
+		if _, err := parseVolumeSize(in); err == nil {
+			t.Fatalf("Expected error for %s", in)
+		}
+	}
+}
+
+func Test_parseDuration(t *testing.T) {
+	durations := map[string]int{
+		"86400": 86400,
+		"30s":   30,
+		"4h":    14400,
+		"7d":    604800,
+		"1h30m": 5400,
+		"2W":    1209600,
+	}
+	for in, expected := range durations {
+		seconds, err := parseDuration(in)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if seconds != expected {
+			t.Fatalf("Wrong value returned for %s: %d", in, seconds)
+		}
+	}
+
+	for _, in := range []string{"", "h", "1.5h", "-1h", "4x", "4h 30m"} {
+		if _, err := parseDuration(in); err == nil {
+			t.Fatalf("Expected error for %s", in)
+		}
+	}
+}
+
+func Test_normalizeDuration(t *testing.T) {
+	if seconds := normalizeDuration("4h"); seconds != "14400" {
+		t.Fatalf("Wrong value returned: %s", seconds)
+	}
+	if seconds := normalizeDuration("14400"); seconds != "14400" {
+		t.Fatalf("Wrong value returned: %s", seconds)
+	}
+}
+
+func Test_parseTimeOfDay(t *testing.T) {
+	times := map[string]int{
+		"0":     0,
+		"00:00": 0,
+		"02:00": 7200,
+		"2:00":  7200,
+		"23:00": 82800,
+		"3600":  3600,
+	}
+	for in, expected := range times {
+		seconds, err := parseTimeOfDay(in)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if seconds != expected {
+			t.Fatalf("Wrong value returned for %s: %d", in, seconds)
+		}
+	}
+
+	for _, in := range []string{"", "2am", "24:00", "02:30", "02:60", "60", "86400"} {
+		if _, err := parseTimeOfDay(in); err == nil {
+			t.Fatalf("Expected error for %s", in)
+		}
+	}
+}
+
+func Test_normalizeTimeOfDay(t *testing.T) {
+	if seconds := normalizeTimeOfDay("02:00"); seconds != "7200" {
+		t.Fatalf("Wrong value returned: %s", seconds)
+	}
+	if seconds := normalizeTimeOfDay("7200"); seconds != "7200" {
+		t.Fatalf("Wrong value returned: %s", seconds)
+	}
+}
+
+func Test_suppressUnsetTimeOfDay(t *testing.T) {
+	if !suppressUnsetTimeOfDay("snap_at", "0", "", nil) {
+		t.Fatal("Expected an unset time to match midnight")
+	}
+	if !suppressUnsetTimeOfDay("snap_at", "7200", "02:00", nil) {
+		t.Fatal("Expected 02:00 to match 7200")
+	}
+	if suppressUnsetTimeOfDay("snap_at", "7200", "", nil) {
+		t.Fatal("Expected an unset time not to match 02:00")
+	}
+}
+
+func Test_validateVolumeSize(t *testing.T) {
+	if _, errs := validateVolumeSize("1G", "size"); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %s", errs)
+	}
+	if _, errs := validateVolumeSize("1000", "size"); len(errs) == 0 {
+		t.Fatal("Expected error for unaligned size")
+	}
+}
+
+func Test_normalizeVolumeSize(t *testing.T) {
+	if size := normalizeVolumeSize("1G"); size != "1073741824" {
+		t.Fatalf("Wrong value returned: %s", size)
+	}
+	if size := normalizeVolumeSize("1073741824"); size != "1073741824" {
+		t.Fatalf("Wrong value returned: %s", size)
+	}
+}
+
+func Test_restVersionAtLeast(t *testing.T) {
+	if !restVersionAtLeast("1.16", "1.14") {
+		t.Fatal("Returned false")
+	}
+	if !restVersionAtLeast("1.17", "1.17") {
+		t.Fatal("Returned false")
+	}
+	if restVersionAtLeast("1.9", "1.14") {
+		t.Fatal("Returned true")
+	}
+	if restVersionAtLeast("", "1.14") {
+		t.Fatal("Returned true")
+	}
+}
+
+func Test_splitConnectionID(t *testing.T) {
+	host, vol, err := splitConnectionID("host1/vgroup/vol1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if host != "host1" || vol != "vgroup/vol1" {
+		t.Fatalf("Wrong values returned: %s, %s", host, vol)
+	}
+
+	for _, id := range []string{"host1", "/vol1", "host1/"} {
+		if _, _, err := splitConnectionID(id); err == nil {
+			t.Fatalf("Expected error for %s", id)
+		}
+	}
+}
+
+func Test_resourceVolumeConnectionHash(t *testing.T) {
+	a := map[string]interface{}{"vol": "vol1", "lun": 1}
+	b := map[string]interface{}{"vol": "vol1", "lun": 2}
+	if resourceVolumeConnectionHash(a) != resourceVolumeConnectionHash(b) {
+		t.Fatal("Hash depends on the LUN")
+	}
+}
+
+func Test_checkVolumeConnectionLuns(t *testing.T) {
+	connected := []flasharray.ConnectedVolume{
+		{Vol: "vol1", Lun: 1},
+		{Vol: "vol2", Lun: 2},
+	}
+
+	connect := []volumeConnection{{vol: "vol3", data: map[string]interface{}{"lun": 1}}}
+	if err := checkVolumeConnectionLuns(connected, nil, connect); err == nil {
+		t.Fatal("Expected error for LUN in use")
+	}
+	if err := checkVolumeConnectionLuns(connected, []string{"vol1"}, connect); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	connect = []volumeConnection{
+		{vol: "vol3", data: map[string]interface{}{"lun": 3}},
+		{vol: "vol4", data: map[string]interface{}{"lun": 3}},
+	}
+	if err := checkVolumeConnectionLuns(connected, nil, connect); err == nil {
+		t.Fatal("Expected error for duplicate LUN")
+	}
+
+	connect = []volumeConnection{
+		{vol: "vol2", data: map[string]interface{}{"lun": 1}},
+		{vol: "vol1", data: map[string]interface{}{"lun": 2}},
+		{vol: "vol3", data: map[string]interface{}{}},
+	}
+	if err := checkVolumeConnectionLuns(connected, []string{"vol1", "vol2"}, connect); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+}
+
+func Test_validateWWN(t *testing.T) {
+	for _, wwn := range []string{"10000000C9123456", "10:00:00:00:c9:12:34:56"} {
+		if _, errs := validateWWN(wwn, "wwn"); len(errs) > 0 {
+			t.Fatalf("Unexpected errors for %s: %s", wwn, errs)
+		}
+	}
+	for _, wwn := range []string{"10000000C91234", "10000000C912345G", ""} {
+		if _, errs := validateWWN(wwn, "wwn"); len(errs) == 0 {
+			t.Fatalf("Expected error for %s", wwn)
+		}
+	}
+}
+
+func Test_normalizeWWN(t *testing.T) {
+	if wwn := normalizeWWN("10:00:00:00:c9:12:34:56"); wwn != "10000000C9123456" {
+		t.Fatalf("Wrong value returned: %s", wwn)
+	}
+}
+
+func Test_validateIQN(t *testing.T) {
+	for _, iqn := range []string{"iqn.1993-08.org.debian:01:abc", "IQN.2010-04.com.example", "eui.02004567A425678D", "naa.52004567BA64678D"} {
+		if _, errs := validateIQN(iqn, "iqn"); len(errs) > 0 {
+			t.Fatalf("Unexpected errors for %s: %s", iqn, errs)
+		}
+	}
+	for _, iqn := range []string{"iqn.93-08.org.debian", "eui.0200", "naa.52004567BA64678", "host1"} {
+		if _, errs := validateIQN(iqn, "iqn"); len(errs) == 0 {
+			t.Fatalf("Expected error for %s", iqn)
+		}
+	}
+}
+
+func Test_normalizeIQN(t *testing.T) {
+	if iqn := normalizeIQN("IQN.1993-08.org.Debian:01:ABC"); iqn != "iqn.1993-08.org.debian:01:abc" {
+		t.Fatalf("Wrong value returned: %s", iqn)
+	}
+	if iqn := normalizeIQN("EUI.02004567a425678d"); iqn != "eui.02004567A425678D" {
+		t.Fatalf("Wrong value returned: %s", iqn)
+	}
+}
+
+func Test_validateNQN(t *testing.T) {
+	nqn := "nqn.2014-08.org.nvmexpress:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6"
+	if _, errs := validateNQN(nqn, "nqn"); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %s", errs)
+	}
+	for _, nqn := range []string{"nqn.2014-08.org.nvmexpress", "iqn.2014-08.org.nvmexpress:uuid:1"} {
+		if _, errs := validateNQN(nqn, "nqn"); len(errs) == 0 {
+			t.Fatalf("Expected error for %s", nqn)
+		}
+	}
+}
+
+func Test_hashSecret(t *testing.T) {
+	if hash := hashSecret(""); hash != "" {
+		t.Fatalf("Wrong value returned: %s", hash)
+	}
+	hash := hashSecret("mysecret")
+	if hash == "mysecret" || len(hash) != 64 {
+		t.Fatalf("Wrong value returned: %s", hash)
+	}
+}
+
+func Test_generateChapSecret(t *testing.T) {
+	a, err := generateChapSecret()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	b, _ := generateChapSecret()
+	if len(a) != 32 || a == b {
+		t.Fatalf("Wrong values returned: %s, %s", a, b)
+	}
+}
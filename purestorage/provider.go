@@ -81,7 +81,14 @@ func Provider() terraform.ResourceProvider {
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"purestorage_flasharray": dataSourcePureFlashArray(),
+			"purestorage_flasharray":      dataSourcePureFlashArray(),
+			"purestorage_volume":          dataSourcePureVolume(),
+			"purestorage_volumes":         dataSourcePureVolumes(),
+			"purestorage_volume_metrics":  dataSourcePureVolumeMetrics(),
+			"purestorage_host":            dataSourcePureHost(),
+			"purestorage_hosts":           dataSourcePureHosts(),
+			"purestorage_hostgroup":       dataSourcePureHostgroup(),
+			"purestorage_protectiongroup": dataSourcePureProtectiongroup(),
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
@@ -89,10 +96,22 @@ func Provider() terraform.ResourceProvider {
 				"purestorage_flasharray",
 				dataSourcePureFlashArray(),
 			),
-			"purestorage_volume":          resourcePureVolume(),
-			"purestorage_host":            resourcePureHost(),
-			"purestorage_hostgroup":       resourcePureHostgroup(),
-			"purestorage_protectiongroup": resourcePureProtectiongroup(),
+			"purestorage_volume":                      resourcePureVolume(),
+			"purestorage_volume_snapshot":             resourcePureVolumeSnapshot(),
+			"purestorage_volume_group":                resourcePureVolumeGroup(),
+			"purestorage_pod":                         resourcePurePod(),
+			"purestorage_offload_nfs":                 resourcePureOffloadNFS(),
+			"purestorage_host":                        resourcePureHost(),
+			"purestorage_host_volume_connection":      resourcePureHostVolumeConnection(),
+			"purestorage_hostgroup":                   resourcePureHostgroup(),
+			"purestorage_hostgroup_member":            resourcePureHostgroupMember(),
+			"purestorage_hostgroup_volume_connection": resourcePureHostgroupVolumeConnection(),
+			"purestorage_protectiongroup":             resourcePureProtectiongroup(),
+			"purestorage_protectiongroup_allow":       resourcePureProtectiongroupAllow(),
+			"purestorage_protectiongroup_volume":      resourcePureProtectiongroupVolume(),
+			"purestorage_protectiongroup_host":        resourcePureProtectiongroupHost(),
+			"purestorage_protectiongroup_hostgroup":   resourcePureProtectiongroupHostgroup(),
+			"purestorage_protectiongroup_snapshot":    resourcePureProtectiongroupSnapshot(),
 		},
 		ConfigureFunc: providerConfigure,
 	}
@@ -37,29 +37,15 @@ func resourcePureHostgroup() *schema.Resource {
 				Required: true,
 			},
 			"hosts": &schema.Schema{
-				Type: schema.TypeList,
+				Type:        schema.TypeList,
+				Description: "List of member hosts. If not provided, the members of the hostgroup are not managed.",
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
 				Optional: true,
-				Default:  nil,
-			},
-			"volume": &schema.Schema{
-				Type:     schema.TypeSet,
-				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"vol": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"lun": {
-							Type:     schema.TypeInt,
-							Required: true,
-						},
-					},
-				},
+				Computed: true,
 			},
+			"volume": schemaVolumeConnections("Shared volume connections of the hostgroup."),
 		},
 	}
 }
@@ -75,6 +61,22 @@ func resourcePureHostgroupCreate(d *schema.ResourceData, m interface{}) error {
 			hosts = append(hosts, element.(string))
 		}
 	}
+
+	if err = checkHostsHgroup(client, d.Get("name").(string), hosts); err != nil {
+		return err
+	}
+
+	_, connectVolumes := volumeConnectionChanges(d)
+	if len(connectVolumes) > 0 {
+		connected, err := listHostsConnections(client, hosts)
+		if err != nil {
+			return err
+		}
+		if err = checkVolumeConnectionLuns(connected, nil, connectVolumes); err != nil {
+			return err
+		}
+	}
+
 	data := map[string][]string{"hostlist": hosts}
 	if hgroup, err = client.Hostgroups.CreateHostgroup(d.Get("name").(string), data); err != nil {
 		return err
@@ -90,16 +92,9 @@ func resourcePureHostgroupCreate(d *schema.ResourceData, m interface{}) error {
 		}
 	}
 
-	if cv := d.Get("volume").(*schema.Set).List(); len(cv) > 0 {
-		for _, volume := range cv {
-			vol, _ := volume.(map[string]interface{})
-			data := make(map[string]interface{})
-			if vol["lun"] != 0 {
-				data["lun"] = vol["lun"].(int)
-			}
-			if _, err := client.Hostgroups.ConnectHostgroup(hgroup.Name, vol["vol"].(string), data); err != nil {
-				return err
-			}
+	for _, c := range connectVolumes {
+		if _, err := client.Hostgroups.ConnectHostgroup(hgroup.Name, c.vol, c.data); err != nil {
+			return err
 		}
 	}
 
@@ -128,12 +123,30 @@ func resourcePureHostgroupRead(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
+// resourcePureHostgroupUpdate updates the hostgroup.  LUN conflicts in the
+// volume connections are checked against every member host before any
+// change is made.
 func resourcePureHostgroupUpdate(d *schema.ResourceData, m interface{}) error {
 	d.Partial(true)
 	client := m.(*flasharray.Client)
 	var h *flasharray.Hostgroup
 	var err error
 
+	disconnectVolumes, connectVolumes := volumeConnectionChanges(d)
+	if len(connectVolumes) > 0 {
+		var hosts []string
+		for _, element := range d.Get("hosts").([]interface{}) {
+			hosts = append(hosts, element.(string))
+		}
+		connected, err := listHostsConnections(client, hosts)
+		if err != nil {
+			return err
+		}
+		if err = checkVolumeConnectionLuns(connected, disconnectVolumes, connectVolumes); err != nil {
+			return err
+		}
+	}
+
 	if d.HasChange("name") {
 		if h, err = client.Hostgroups.RenameHostgroup(d.Id(), d.Get("name").(string)); err != nil {
 			return err
@@ -147,6 +160,9 @@ func resourcePureHostgroupUpdate(d *schema.ResourceData, m interface{}) error {
 		for _, element := range d.Get("hosts").([]interface{}) {
 			hosts = append(hosts, element.(string))
 		}
+		if err = checkHostsHgroup(client, d.Id(), hosts); err != nil {
+			return err
+		}
 		data := map[string][]string{"hostlist": hosts}
 		if _, err = client.Hostgroups.SetHostgroup(d.Id(), data); err != nil {
 			return err
@@ -154,40 +170,15 @@ func resourcePureHostgroupUpdate(d *schema.ResourceData, m interface{}) error {
 	}
 	d.SetPartial("hosts")
 
-	if d.HasChange("volume") {
-		o, n := d.GetChange("volume")
-		if o == nil {
-			o = new(schema.Set)
-		}
-		if n == nil {
-			n = new(schema.Set)
-		}
-
-		os := o.(*schema.Set)
-		ns := n.(*schema.Set)
-		disconnectVolumes := os.Difference(ns).List()
-		connectVolumes := ns.Difference(os).List()
-
-		if len(connectVolumes) > 0 {
-			for _, volume := range connectVolumes {
-				data := make(map[string]interface{})
-				vol := volume.(map[string]interface{})
-				if vol["lun"] != 0 {
-					data["lun"] = vol["lun"].(int)
-				}
-				if _, err = client.Hostgroups.ConnectHostgroup(d.Id(), vol["vol"].(string), data); err != nil {
-					return err
-				}
-			}
+	for _, vol := range disconnectVolumes {
+		if _, err = client.Hostgroups.DisconnectHostgroup(d.Id(), vol); err != nil {
+			return err
 		}
+	}
 
-		if len(disconnectVolumes) > 0 {
-			for _, volume := range disconnectVolumes {
-				vol := volume.(map[string]interface{})
-				if _, err = client.Hostgroups.DisconnectHostgroup(d.Id(), vol["vol"].(string)); err != nil {
-					return err
-				}
-			}
+	for _, c := range connectVolumes {
+		if _, err = client.Hostgroups.ConnectHostgroup(d.Id(), c.vol, c.data); err != nil {
+			return err
 		}
 	}
 
@@ -241,3 +232,32 @@ func resourcePureHostgroupImport(d *schema.ResourceData, m interface{}) ([]*sche
 	d.Set("hosts", h.Hosts)
 	return []*schema.ResourceData{d}, nil
 }
+
+// listHostsConnections returns the private and shared volume connections
+// of each of the hosts.
+func listHostsConnections(client *flasharray.Client, hosts []string) ([]flasharray.ConnectedVolume, error) {
+	var connected []flasharray.ConnectedVolume
+	for _, host := range hosts {
+		connections, err := client.Hosts.ListHostConnections(host, nil)
+		if err != nil {
+			return nil, err
+		}
+		connected = append(connected, connections...)
+	}
+	return connected, nil
+}
+
+// checkHostsHgroup returns an error if any of the hosts is already a member
+// of a hostgroup other than hgroup.
+func checkHostsHgroup(client *flasharray.Client, hgroup string, hosts []string) error {
+	for _, name := range hosts {
+		host, err := client.Hosts.GetHost(name, nil)
+		if err != nil {
+			return err
+		}
+		if host.Hgroup != "" && host.Hgroup != hgroup {
+			return hostHgroupConflictError(host.Name, host.Hgroup)
+		}
+	}
+	return nil
+}
@@ -17,6 +17,8 @@
 package purestorage
 
 import (
+	"fmt"
+
 	"github.com/devans10/pugo/flasharray"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/helper/validation"
@@ -31,44 +33,41 @@ func resourcePureHost() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: resourcePureHostImport,
 		},
+		CustomizeDiff: resourcePureHostCustomizeDiff,
+		SchemaVersion: 2,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    resourcePureHostV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourcePureHostStateUpgradeV0,
+				Version: 0,
+			},
+			{
+				Type:    resourcePureHostV1().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourcePureHostStateUpgradeV1,
+				Version: 1,
+			},
+		},
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:        schema.TypeString,
 				Description: "Name of the host",
 				Required:    true,
 			},
-			"iqn": &schema.Schema{
-				Type:        schema.TypeList,
-				Description: "List of iSCSI qualified names (IQNs) to the specified host.",
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
-				},
-				Required: false,
-				Optional: true,
-			},
-			"wwn": &schema.Schema{
-				Type:        schema.TypeList,
-				Description: "List of Fibre Channel worldwide names (WWNs) to the specified host.",
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
-				},
-				Required: false,
-				Optional: true,
-			},
-			"nqn": &schema.Schema{
-				Type:        schema.TypeList,
-				Description: "List of NVMeF qualified names (NQNs) to the specified host.",
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
-				},
-				Required: false,
-				Optional: true,
-			},
+			"iqn": schemaInitiators("Set of iSCSI qualified names (IQNs) to the specified host.", normalizeIQN, validateIQN),
+			"wwn": schemaInitiators("Set of Fibre Channel worldwide names (WWNs) to the specified host.", normalizeWWN, validateWWN),
+			"nqn": schemaInitiators("Set of NVMeF qualified names (NQNs) to the specified host.", normalizeNQN, validateNQN),
 			"host_password": &schema.Schema{
 				Type:        schema.TypeString,
-				Description: "Host password for CHAP authentication.",
-				Computed:    true,
+				Description: "Host password for CHAP authentication. Only a hash of the password is stored in the state.",
 				Optional:    true,
+				Sensitive:   true,
+				StateFunc:   hashSecret,
+			},
+			"generated_host_password": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Host CHAP password generated when generate_chap_secrets is set.",
+				Computed:    true,
+				Sensitive:   true,
 			},
 			"host_user": &schema.Schema{
 				Type:        schema.TypeString,
@@ -93,14 +92,29 @@ func resourcePureHost() *schema.Resource {
 				Default:  nil,
 			},
 			"hgroup": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:        schema.TypeString,
+				Description: "Hostgroup the host is a member of. If not provided, the hostgroup membership of the host is not managed.",
+				Optional:    true,
+				Computed:    true,
 			},
 			"target_password": &schema.Schema{
 				Type:        schema.TypeString,
-				Description: "Target password for CHAP authentication.",
+				Description: "Target password for CHAP authentication. Only a hash of the password is stored in the state.",
+				Optional:    true,
+				Sensitive:   true,
+				StateFunc:   hashSecret,
+			},
+			"generated_target_password": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Target CHAP password generated when generate_chap_secrets is set.",
 				Computed:    true,
+				Sensitive:   true,
+			},
+			"generate_chap_secrets": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: "Generate random CHAP passwords for host_password and target_password if they are not provided.",
 				Optional:    true,
+				Default:     false,
 			},
 			"target_user": &schema.Schema{
 				Type:        schema.TypeString,
@@ -108,22 +122,7 @@ func resourcePureHost() *schema.Resource {
 				Optional:    true,
 				Default:     "",
 			},
-			"volume": &schema.Schema{
-				Type:     schema.TypeSet,
-				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"vol": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"lun": {
-							Type:     schema.TypeInt,
-							Required: true,
-						},
-					},
-				},
-			},
+			"volume": schemaVolumeConnections("Private volume connections of the host."),
 		},
 	}
 }
@@ -135,32 +134,25 @@ func resourcePureHostCreate(d *schema.ResourceData, m interface{}) error {
 	var h *flasharray.Host
 	var err error
 
+	_, connectVolumes := volumeConnectionChanges(d)
+	if err = checkVolumeConnectionLuns(nil, nil, connectVolumes); err != nil {
+		return err
+	}
+
 	v, _ := d.GetOk("name")
 
 	data := make(map[string]interface{})
 
 	if wl, ok := d.GetOk("wwn"); ok {
-		var wwnlist []string
-		for _, element := range wl.([]interface{}) {
-			wwnlist = append(wwnlist, element.(string))
-		}
-		data["wwnlist"] = wwnlist
+		data["wwnlist"] = expandInitiators(wl.(*schema.Set), normalizeWWN)
 	}
 
 	if il, ok := d.GetOk("iqn"); ok {
-		var iqnlist []string
-		for _, element := range il.([]interface{}) {
-			iqnlist = append(iqnlist, element.(string))
-		}
-		data["iqnlist"] = iqnlist
+		data["iqnlist"] = expandInitiators(il.(*schema.Set), normalizeIQN)
 	}
 
 	if nl, ok := d.GetOk("nqn"); ok {
-		var nqnlist []string
-		for _, element := range nl.([]interface{}) {
-			nqnlist = append(nqnlist, element.(string))
-		}
-		data["nqnlist"] = nqnlist
+		data["nqnlist"] = expandInitiators(nl.(*schema.Set), normalizeNQN)
 	}
 
 	if pa, ok := d.GetOk("preferred_array"); ok {
@@ -189,19 +181,15 @@ func resourcePureHostCreate(d *schema.ResourceData, m interface{}) error {
 	d.SetPartial("nqn")
 	d.SetPartial("preferred_array")
 
-	chapDetails := make(map[string]interface{})
-	if hostPassword, ok := d.GetOk("host_password"); ok {
-		chapDetails["host_password"] = hostPassword.(string)
+	chapDetails, err := chapSecrets(d)
+	if err != nil {
+		return err
 	}
 
 	if hostUser, ok := d.GetOk("host_user"); ok {
 		chapDetails["host_user"] = hostUser.(string)
 	}
 
-	if targetPassword, ok := d.GetOk("target_password"); ok {
-		chapDetails["target_password"] = targetPassword.(string)
-	}
-
 	if targetUser, ok := d.GetOk("target_user"); ok {
 		chapDetails["target_user"] = targetUser.(string)
 	}
@@ -225,16 +213,16 @@ func resourcePureHostCreate(d *schema.ResourceData, m interface{}) error {
 	}
 	d.SetPartial("personality")
 
-	if cv := d.Get("volume").(*schema.Set).List(); len(cv) > 0 {
-		for _, volume := range cv {
-			vol, _ := volume.(map[string]interface{})
-			data := make(map[string]interface{})
-			if vol["lun"] != 0 {
-				data["lun"] = vol["lun"].(int)
-			}
-			if _, err := client.Hosts.ConnectHost(h.Name, vol["vol"].(string), data); err != nil {
-				return err
-			}
+	if hgroup, ok := d.GetOk("hgroup"); ok {
+		if err = setHostHgroup(client, h.Name, "", hgroup.(string)); err != nil {
+			return err
+		}
+	}
+	d.SetPartial("hgroup")
+
+	for _, c := range connectVolumes {
+		if _, err := client.Hosts.ConnectHost(h.Name, c.vol, c.data); err != nil {
+			return err
 		}
 	}
 
@@ -263,6 +251,7 @@ func resourcePureHostRead(d *schema.ResourceData, m interface{}) error {
 	d.Set("iqn", host.Iqn)
 	d.Set("wwn", host.Wwn)
 	d.Set("nqn", host.Nqn)
+	d.Set("hgroup", host.Hgroup)
 
 	host, _ = client.Hosts.GetHost(d.Id(), map[string]string{"preferred_array": "true"})
 	d.Set("preferred_array", host.PreferredArray)
@@ -271,20 +260,32 @@ func resourcePureHostRead(d *schema.ResourceData, m interface{}) error {
 	d.Set("personality", host.Personality)
 
 	host, _ = client.Hosts.GetHost(d.Id(), map[string]string{"chap": "true"})
-	d.Set("host_password", host.HostPassword)
 	d.Set("host_user", host.HostUser)
-	d.Set("target_password", host.TargetPassword)
 	d.Set("target_user", host.TargetUser)
+	readChapSecrets(d, host)
 
 	return nil
 }
 
+// resourcePureHostUpdate updates the host.  LUN conflicts in the volume
+// connections are checked before any change is made.
 func resourcePureHostUpdate(d *schema.ResourceData, m interface{}) error {
 	d.Partial(true)
 	client := m.(*flasharray.Client)
 	var h *flasharray.Host
 	var err error
 
+	disconnectVolumes, connectVolumes := volumeConnectionChanges(d)
+	if len(connectVolumes) > 0 {
+		connected, err := client.Hosts.ListHostConnections(d.Id(), nil)
+		if err != nil {
+			return err
+		}
+		if err = checkVolumeConnectionLuns(connected, disconnectVolumes, connectVolumes); err != nil {
+			return err
+		}
+	}
+
 	if d.HasChange("name") {
 		if h, err = client.Hosts.RenameHost(d.Id(), d.Get("name").(string)); err != nil {
 			return err
@@ -294,12 +295,7 @@ func resourcePureHostUpdate(d *schema.ResourceData, m interface{}) error {
 	d.SetPartial("name")
 
 	if d.HasChange("wwn") {
-		var wwnlist []string
-		wl, _ := d.GetOk("wwn")
-		for _, element := range wl.([]interface{}) {
-			wwnlist = append(wwnlist, element.(string))
-		}
-		data := map[string]interface{}{"wwnlist": wwnlist}
+		data := map[string]interface{}{"wwnlist": expandInitiators(d.Get("wwn").(*schema.Set), normalizeWWN)}
 		if _, err = client.Hosts.SetHost(d.Id(), data); err != nil {
 			return err
 		}
@@ -307,12 +303,7 @@ func resourcePureHostUpdate(d *schema.ResourceData, m interface{}) error {
 	d.SetPartial("wwn")
 
 	if d.HasChange("iqn") {
-		var iqnlist []string
-		il, _ := d.GetOk("iqn")
-		for _, element := range il.([]interface{}) {
-			iqnlist = append(iqnlist, element.(string))
-		}
-		data := map[string]interface{}{"iqnlist": iqnlist}
+		data := map[string]interface{}{"iqnlist": expandInitiators(d.Get("iqn").(*schema.Set), normalizeIQN)}
 		if _, err = client.Hosts.SetHost(d.Id(), data); err != nil {
 			return err
 		}
@@ -320,12 +311,7 @@ func resourcePureHostUpdate(d *schema.ResourceData, m interface{}) error {
 	d.SetPartial("iqn")
 
 	if d.HasChange("nqn") {
-		var nqnlist []string
-		nl, _ := d.GetOk("nqn")
-		for _, element := range nl.([]interface{}) {
-			nqnlist = append(nqnlist, element.(string))
-		}
-		data := map[string]interface{}{"nqnlist": nqnlist}
+		data := map[string]interface{}{"nqnlist": expandInitiators(d.Get("nqn").(*schema.Set), normalizeNQN)}
 		if _, err = client.Hosts.SetHost(d.Id(), data); err != nil {
 			return err
 		}
@@ -345,20 +331,15 @@ func resourcePureHostUpdate(d *schema.ResourceData, m interface{}) error {
 	}
 	d.SetPartial("preferred_array")
 
-	chapDetails := make(map[string]interface{})
-
-	if d.HasChange("host_password") {
-		chapDetails["host_password"] = d.Get("host_password").(string)
+	chapDetails, err := chapSecrets(d)
+	if err != nil {
+		return err
 	}
 
 	if d.HasChange("host_user") {
 		chapDetails["host_user"] = d.Get("host_user").(string)
 	}
 
-	if d.HasChange("target_password") {
-		chapDetails["target_password"] = d.Get("target_password").(string)
-	}
-
 	if d.HasChange("target_user") {
 		chapDetails["target_user"] = d.Get("target_user").(string)
 	}
@@ -380,40 +361,30 @@ func resourcePureHostUpdate(d *schema.ResourceData, m interface{}) error {
 	}
 	d.SetPartial("personality")
 
-	if d.HasChange("volume") {
-		o, n := d.GetChange("volume")
-		if o == nil {
-			o = new(schema.Set)
+	if d.HasChange("hgroup") {
+		o, n := d.GetChange("hgroup")
+		host, err := client.Hosts.GetHost(d.Id(), nil)
+		if err != nil {
+			return err
 		}
-		if n == nil {
-			n = new(schema.Set)
+		if host.Hgroup != "" && host.Hgroup != o.(string) && host.Hgroup != n.(string) {
+			return hostHgroupConflictError(d.Id(), host.Hgroup)
 		}
+		if err = setHostHgroup(client, d.Id(), host.Hgroup, n.(string)); err != nil {
+			return err
+		}
+	}
+	d.SetPartial("hgroup")
 
-		os := o.(*schema.Set)
-		ns := n.(*schema.Set)
-		disconnectVolumes := os.Difference(ns).List()
-		connectVolumes := ns.Difference(os).List()
-
-		if len(connectVolumes) > 0 {
-			for _, volume := range connectVolumes {
-				data := make(map[string]interface{})
-				vol := volume.(map[string]interface{})
-				if vol["lun"] != 0 {
-					data["lun"] = vol["lun"].(int)
-				}
-				if _, err = client.Hosts.ConnectHost(d.Id(), vol["vol"].(string), data); err != nil {
-					return err
-				}
-			}
+	for _, vol := range disconnectVolumes {
+		if _, err = client.Hosts.DisconnectHost(d.Id(), vol); err != nil {
+			return err
 		}
+	}
 
-		if len(disconnectVolumes) > 0 {
-			for _, volume := range disconnectVolumes {
-				vol := volume.(map[string]interface{})
-				if _, err = client.Hosts.DisconnectHost(d.Id(), vol["vol"].(string)); err != nil {
-					return err
-				}
-			}
+	for _, c := range connectVolumes {
+		if _, err = client.Hosts.ConnectHost(d.Id(), c.vol, c.data); err != nil {
+			return err
 		}
 	}
 	d.Partial(false)
@@ -432,6 +403,12 @@ func resourcePureHostDelete(d *schema.ResourceData, m interface{}) error {
 		}
 	}
 
+	if hgroup := d.Get("hgroup").(string); hgroup != "" {
+		if err := setHostHgroup(client, d.Id(), hgroup, ""); err != nil {
+			return err
+		}
+	}
+
 	if _, err := client.Hosts.DeleteHost(d.Id()); err != nil {
 		return err
 	}
@@ -459,6 +436,7 @@ func resourcePureHostImport(d *schema.ResourceData, m interface{}) ([]*schema.Re
 	d.Set("iqn", host.Iqn)
 	d.Set("wwn", host.Wwn)
 	d.Set("nqn", host.Nqn)
+	d.Set("hgroup", host.Hgroup)
 
 	host, _ = client.Hosts.GetHost(d.Id(), map[string]string{"preferred_array": "true"})
 	d.Set("preferred_array", host.PreferredArray)
@@ -467,10 +445,109 @@ func resourcePureHostImport(d *schema.ResourceData, m interface{}) ([]*schema.Re
 	d.Set("personality", host.Personality)
 
 	host, _ = client.Hosts.GetHost(d.Id(), map[string]string{"chap": "true"})
-	d.Set("host_password", host.HostPassword)
 	d.Set("host_user", host.HostUser)
-	d.Set("target_password", host.TargetPassword)
 	d.Set("target_user", host.TargetUser)
+	d.Set("generate_chap_secrets", false)
 
 	return []*schema.ResourceData{d}, nil
 }
+
+// setHostHgroup moves the host from its current hostgroup, if any, to the
+// given hostgroup.  An empty hgroup only removes the host from its current
+// hostgroup.
+func setHostHgroup(client *flasharray.Client, host string, current string, hgroup string) error {
+	if current == hgroup {
+		return nil
+	}
+
+	if current != "" {
+		if _, err := client.Hostgroups.SetHostgroup(current, map[string][]string{"remhostlist": {host}}); err != nil {
+			return err
+		}
+	}
+
+	if hgroup != "" {
+		if _, err := client.Hostgroups.SetHostgroup(hgroup, map[string][]string{"addhostlist": {host}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hostHgroupConflictError explains that the hostgroup membership of a host
+// is being managed from both the host and the hostgroup.
+func hostHgroupConflictError(host string, hgroup string) error {
+	return fmt.Errorf("host %s is already a member of hostgroup %s. A host can only be a member of one hostgroup, "+
+		"and its membership should be managed either by the hgroup argument of purestorage_host "+
+		"or by the hosts argument of purestorage_hostgroup, not both", host, hgroup)
+}
+
+// resourcePureHostCustomizeDiff plans a new CHAP password when
+// generate_chap_secrets is set and a password was neither provided nor
+// generated, or the generated password was removed from the array.
+func resourcePureHostCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if !d.Get("generate_chap_secrets").(bool) {
+		return nil
+	}
+
+	for _, key := range []string{"host_password", "target_password"} {
+		if d.Get(key).(string) == "" && d.Get("generated_"+key).(string) == "" {
+			if err := d.SetNewComputed("generated_" + key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// chapSecrets returns the CHAP passwords to set on the host.  A password
+// is generated for each one that is not provided if generate_chap_secrets
+// is set, and a generated password is cleared when it is no longer needed.
+func chapSecrets(d *schema.ResourceData) (map[string]interface{}, error) {
+	secrets := make(map[string]interface{})
+	generate := d.Get("generate_chap_secrets").(bool)
+
+	for _, key := range []string{"host_password", "target_password"} {
+		generatedKey := "generated_" + key
+
+		// Changed passwords are read from the configuration, not the hash.
+		// A password removed from the configuration is cleared, unless one
+		// is generated below.
+		if d.HasChange(key) {
+			secrets[key] = d.Get(key).(string)
+		}
+
+		switch {
+		case d.Get(key).(string) != "" || !generate:
+			if d.Get(generatedKey).(string) != "" {
+				d.Set(generatedKey, "")
+				if _, ok := secrets[key]; !ok {
+					secrets[key] = ""
+				}
+			}
+		case d.Get(generatedKey).(string) == "":
+			secret, err := generateChapSecret()
+			if err != nil {
+				return nil, err
+			}
+			d.Set(generatedKey, secret)
+			secrets[key] = secret
+		}
+	}
+	return secrets, nil
+}
+
+// readChapSecrets clears the CHAP passwords that are no longer set on the
+// array.  The array does not return the passwords themselves, so a
+// password changed outside of Terraform cannot be detected.
+func readChapSecrets(d *schema.ResourceData, host *flasharray.Host) {
+	for key, value := range map[string]string{
+		"host_password":   host.HostPassword,
+		"target_password": host.TargetPassword,
+	} {
+		if value == "" {
+			d.Set(key, "")
+			d.Set("generated_"+key, "")
+		}
+	}
+}
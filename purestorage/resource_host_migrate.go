@@ -0,0 +1,132 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// resourcePureHostV0 is the host schema before the initiator names were
+// sets.
+func resourcePureHostV0() *schema.Resource {
+	stringList := func() *schema.Schema {
+		return &schema.Schema{
+			Type: schema.TypeList,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+			Optional: true,
+		}
+	}
+
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"iqn":             stringList(),
+			"wwn":             stringList(),
+			"nqn":             stringList(),
+			"preferred_array": stringList(),
+			"host_password": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"host_user": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"personality": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"hgroup": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"target_password": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"target_user": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"volume": &schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"vol": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"lun": {
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// resourcePureHostStateUpgradeV0 normalizes the initiator names, which are
+// now stored as sets.
+func resourcePureHostStateUpgradeV0(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	for key, normalize := range map[string]func(string) string{
+		"wwn": normalizeWWN,
+		"iqn": normalizeIQN,
+		"nqn": normalizeNQN,
+	} {
+		names, ok := rawState[key].([]interface{})
+		if !ok {
+			continue
+		}
+		for i, name := range names {
+			if s, ok := name.(string); ok {
+				names[i] = normalize(s)
+			}
+		}
+	}
+	return rawState, nil
+}
+
+// resourcePureHostV1 is the host schema before only hashes of the CHAP
+// passwords were stored.
+func resourcePureHostV1() *schema.Resource {
+	r := resourcePureHostV0()
+	for _, key := range []string{"iqn", "wwn", "nqn"} {
+		r.Schema[key].Type = schema.TypeSet
+	}
+	return r
+}
+
+// resourcePureHostStateUpgradeV1 replaces the CHAP passwords with their
+// hashes.
+func resourcePureHostStateUpgradeV1(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	for _, key := range []string{"host_password", "target_password"} {
+		if password, ok := rawState[key].(string); ok {
+			rawState[key] = hashSecret(password)
+		}
+	}
+	return rawState, nil
+}
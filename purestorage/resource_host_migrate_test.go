@@ -0,0 +1,59 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"testing"
+)
+
+func Test_resourcePureHostStateUpgradeV0(t *testing.T) {
+	rawState := map[string]interface{}{
+		"name": "host",
+		"wwn":  []interface{}{"10:00:00:00:c9:12:34:56"},
+		"iqn":  []interface{}{"IQN.1993-08.org.debian:01:abc"},
+	}
+
+	state, err := resourcePureHostStateUpgradeV0(rawState, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if wwn := state["wwn"].([]interface{})[0]; wwn != "10000000C9123456" {
+		t.Fatalf("Wrong value returned: %v", wwn)
+	}
+	if iqn := state["iqn"].([]interface{})[0]; iqn != "iqn.1993-08.org.debian:01:abc" {
+		t.Fatalf("Wrong value returned: %v", iqn)
+	}
+}
+
+func Test_resourcePureHostStateUpgradeV1(t *testing.T) {
+	rawState := map[string]interface{}{
+		"name":            "host",
+		"host_password":   "myhostpassword",
+		"target_password": "",
+	}
+
+	state, err := resourcePureHostStateUpgradeV1(rawState, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if state["host_password"] != hashSecret("myhostpassword") {
+		t.Fatalf("Wrong value returned: %v", state["host_password"])
+	}
+	if state["target_password"] != "" {
+		t.Fatalf("Wrong value returned: %v", state["target_password"])
+	}
+}
@@ -114,6 +114,27 @@ func TestAccResourcePureHost_createWithCHAP(t *testing.T) {
 }
 */
 
+func TestAccResourcePureHost_createWithGeneratedCHAP(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureHostDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureHostConfigWithGeneratedCHAP(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureHostExists(testAccCheckPureHostResourceName, true),
+					resource.TestCheckResourceAttrSet(testAccCheckPureHostResourceName, "generated_host_password"),
+					resource.TestCheckResourceAttrSet(testAccCheckPureHostResourceName, "generated_target_password"),
+					resource.TestCheckResourceAttr(testAccCheckPureHostResourceName, "host_password", ""),
+				),
+			},
+		},
+	})
+}
+
 func TestAccResourcePureHost_createWithPrivateAndSharedVolumes(t *testing.T) {
 	rInt := rand.Int()
 
@@ -216,6 +237,61 @@ func TestAccResourcePureHost_update_AddandRemoveVolume(t *testing.T) {
 	})
 }
 
+func TestAccResourcePureHost_update_changeLun(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureHostDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureHostConfigWithVolumeWithoutLun(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureHostVolumeConnection(testAccCheckPureHostResourceName, fmt.Sprintf("tfhosttest-volume-%d", rInt), true),
+				),
+			},
+			{
+				Config: testAccCheckPureHostConfigWithVolume(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureHostVolumeLun(testAccCheckPureHostResourceName, fmt.Sprintf("tfhosttest-volume-%d", rInt), 1),
+				),
+			},
+			{
+				Config: testAccCheckPureHostConfigWithVolumeWithoutLun(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureHostVolumeLun(testAccCheckPureHostResourceName, fmt.Sprintf("tfhosttest-volume-%d", rInt), 1),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourcePureHost_update_hgroup(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureHostDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureHostConfigWithHgroup(rInt, "a"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureHostExists(testAccCheckPureHostResourceName, true),
+					resource.TestCheckResourceAttr(testAccCheckPureHostResourceName, "hgroup", fmt.Sprintf("tfhosttest-hgroup-a-%d", rInt)),
+				),
+			},
+			{
+				Config: testAccCheckPureHostConfigWithHgroup(rInt, "b"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccCheckPureHostResourceName, "hgroup", fmt.Sprintf("tfhosttest-hgroup-b-%d", rInt)),
+				),
+			},
+		},
+	})
+}
+
 /*
 func TestAccResourcePureHost_update_withCHAP(t *testing.T) {
 	rInt := rand.Int()
@@ -248,6 +324,50 @@ func TestAccResourcePureHost_update_withCHAP(t *testing.T) {
 }
 */
 
+// Replace CHAP passwords with generated ones, then clear them
+func TestAccResourcePureHost_update_CHAPPasswords(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureHostDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureHostConfigWithCHAPPasswords(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureHostExists(testAccCheckPureHostResourceName, true),
+					resource.TestCheckResourceAttr(testAccCheckPureHostResourceName, "host_password", hashSecret("tfhostpassword01")),
+					resource.TestCheckResourceAttr(testAccCheckPureHostResourceName, "target_password", hashSecret("tftargetpassword01")),
+					resource.TestCheckResourceAttr(testAccCheckPureHostResourceName, "generated_host_password", ""),
+					testAccCheckPureHostCHAP(testAccCheckPureHostResourceName, "host_password", "", false),
+					testAccCheckPureHostCHAP(testAccCheckPureHostResourceName, "target_password", "", false),
+				),
+			},
+			{
+				Config: testAccCheckPureHostConfigWithGeneratedCHAP(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccCheckPureHostResourceName, "host_password", ""),
+					resource.TestCheckResourceAttr(testAccCheckPureHostResourceName, "target_password", ""),
+					resource.TestCheckResourceAttrSet(testAccCheckPureHostResourceName, "generated_host_password"),
+					resource.TestCheckResourceAttrSet(testAccCheckPureHostResourceName, "generated_target_password"),
+					testAccCheckPureHostCHAP(testAccCheckPureHostResourceName, "host_password", "", false),
+					testAccCheckPureHostCHAP(testAccCheckPureHostResourceName, "target_password", "", false),
+				),
+			},
+			{
+				Config: testAccCheckPureHostConfigWithCHAP(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccCheckPureHostResourceName, "generated_host_password", ""),
+					resource.TestCheckResourceAttr(testAccCheckPureHostResourceName, "generated_target_password", ""),
+					testAccCheckPureHostCHAP(testAccCheckPureHostResourceName, "host_password", "", true),
+					testAccCheckPureHostCHAP(testAccCheckPureHostResourceName, "target_password", "", true),
+				),
+			},
+		},
+	})
+}
+
 func TestAccResourcePureHost_update_withPersonality(t *testing.T) {
 	rInt := rand.Int()
 
@@ -380,6 +500,25 @@ func testAccCheckPureHostVolumeConnection(n string, volume string, exists bool)
 	}
 }
 
+func testAccCheckPureHostVolumeLun(n string, volume string, lun int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		client := testAccProvider.Meta().(*flasharray.Client)
+		connection, err := getHostVolumeConnection(client, rs.Primary.Attributes["name"], volume)
+		if err != nil || connection == nil {
+			return fmt.Errorf("volume %s not connected to host", volume)
+		}
+		if connection.Lun != lun {
+			return fmt.Errorf("volume %s connected with LUN %d, expected %d", volume, connection.Lun, lun)
+		}
+		return nil
+	}
+}
+
 func testAccCheckPureHostCHAP(n string, param string, value string, exists bool) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -518,6 +657,46 @@ resource "purestorage_host" "tfhosttest" {
 }`, rInt, rInt)
 }
 
+func testAccCheckPureHostConfigWithVolumeWithoutLun(rInt int) string {
+	return fmt.Sprintf(`
+resource "purestorage_volume" "tfhosttest-volume" {
+	name = "tfhosttest-volume-%d"
+	size = 1024000000
+}
+resource "purestorage_host" "tfhosttest" {
+	name = "tfhosttest%d"
+	wwn = ["0000999900009999"]
+	volume {
+		vol = "${purestorage_volume.tfhosttest-volume.name}"
+	}
+}`, rInt, rInt)
+}
+
+func testAccCheckPureHostConfigWithHgroup(rInt int, hgroup string) string {
+	return fmt.Sprintf(`
+resource "purestorage_hostgroup" "tfhosttest-hgroup-a" {
+	name = "tfhosttest-hgroup-a-%d"
+}
+resource "purestorage_hostgroup" "tfhosttest-hgroup-b" {
+	name = "tfhosttest-hgroup-b-%d"
+}
+resource "purestorage_host" "tfhosttest" {
+	name   = "tfhosttest%d"
+	hgroup = "${purestorage_hostgroup.tfhosttest-hgroup-%s.name}"
+}`, rInt, rInt, rInt, hgroup)
+}
+
+func testAccCheckPureHostConfigWithGeneratedCHAP(rInt int) string {
+	return fmt.Sprintf(`
+resource "purestorage_host" "tfhosttest" {
+	name                  = "tfhosttest%d"
+	iqn                   = ["iqn.1993-08.org.debian:01:tfhosttest%d"]
+	host_user             = "myhostuser"
+	target_user           = "mytargetuser"
+	generate_chap_secrets = true
+}`, rInt, rInt)
+}
+
 func testAccCheckPureHostConfigWithoutVolume(rInt int) string {
 	return fmt.Sprintf(`
 resource "purestorage_volume" "tfhosttest-volume" {
@@ -539,6 +718,18 @@ resource "purestorage_host" "tfhosttest" {
 }`, rInt)
 }
 
+func testAccCheckPureHostConfigWithCHAPPasswords(rInt int) string {
+	return fmt.Sprintf(`
+resource "purestorage_host" "tfhosttest" {
+	name            = "tfhosttest%d"
+	iqn             = ["iqn.1993-08.org.debian:01:tfhosttest%d"]
+	host_user       = "myhostuser"
+	host_password   = "tfhostpassword01"
+	target_user     = "mytargetuser"
+	target_password = "tftargetpassword01"
+}`, rInt, rInt)
+}
+
 func testAccCheckPureHostConfigWithPersonality(rInt int) string {
 	return fmt.Sprintf(`
 resource "purestorage_host" "tfhosttest" {
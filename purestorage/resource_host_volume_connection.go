@@ -0,0 +1,153 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"fmt"
+
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourcePureHostVolumeConnection() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePureHostVolumeConnectionCreate,
+		Read:   resourcePureHostVolumeConnectionRead,
+		Delete: resourcePureHostVolumeConnectionDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourcePureHostVolumeConnectionImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"host": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Name of the host.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"volume": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Name of the volume to connect to the host.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"lun": &schema.Schema{
+				Type:         schema.TypeInt,
+				Description:  "LUN ID of the connection. If not provided, the array assigns the next available LUN.",
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntBetween(0, 16383),
+			},
+		},
+	}
+}
+
+// resourcePureHostVolumeConnectionCreate creates a private connection
+// between a host and a volume.
+func resourcePureHostVolumeConnectionCreate(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	host := d.Get("host").(string)
+	volume := d.Get("volume").(string)
+
+	data := make(map[string]interface{})
+	if lun, ok := d.GetOkExists("lun"); ok {
+		data["lun"] = lun.(int)
+	}
+
+	if _, err := client.Hosts.ConnectHost(host, volume, data); err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", host, volume))
+	return resourcePureHostVolumeConnectionRead(d, m)
+}
+
+func resourcePureHostVolumeConnectionRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	host, volume, err := splitConnectionID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	connection, _ := getHostVolumeConnection(client, host, volume)
+
+	if connection == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("host", connection.Name)
+	d.Set("volume", connection.Vol)
+	d.Set("lun", connection.Lun)
+	return nil
+}
+
+func resourcePureHostVolumeConnectionDelete(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	if _, err := client.Hosts.DisconnectHost(d.Get("host").(string), d.Get("volume").(string)); err != nil {
+		return err
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourcePureHostVolumeConnectionImport imports a connection into
+// Terraform.  The ID must be in the form host/vol.
+func resourcePureHostVolumeConnectionImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*flasharray.Client)
+
+	host, volume, err := splitConnectionID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	connection, err := getHostVolumeConnection(client, host, volume)
+	if err != nil {
+		return nil, err
+	}
+
+	if connection == nil {
+		return nil, fmt.Errorf("volume %s is not connected to host %s", volume, host)
+	}
+
+	d.Set("host", connection.Name)
+	d.Set("volume", connection.Vol)
+	d.Set("lun", connection.Lun)
+	return []*schema.ResourceData{d}, nil
+}
+
+// getHostVolumeConnection returns the private connection between the host
+// and volume, or nil if they are not connected.
+func getHostVolumeConnection(client *flasharray.Client, host string, volume string) (*flasharray.ConnectedVolume, error) {
+	connections, err := client.Hosts.ListHostConnections(host, map[string]string{"private": "true"})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range connections {
+		if c.Vol == volume {
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
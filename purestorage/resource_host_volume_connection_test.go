@@ -0,0 +1,131 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+const testAccCheckPureHostVolumeConnectionResourceName = "purestorage_host_volume_connection.tfconnectiontest"
+
+func TestAccResourcePureHostVolumeConnection_create(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureHostVolumeConnectionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureHostVolumeConnectionConfig(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureHostVolumeConnectionExists(testAccCheckPureHostVolumeConnectionResourceName, true),
+					resource.TestCheckResourceAttr(testAccCheckPureHostVolumeConnectionResourceName, "lun", "10"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourcePureHostVolumeConnection_import(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureHostVolumeConnectionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureHostVolumeConnectionConfig(rInt),
+			},
+			{
+				ResourceName:      testAccCheckPureHostVolumeConnectionResourceName,
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("tfconnectiontest%d/tfconnectiontest-volume-%d", rInt, rInt),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckPureHostVolumeConnectionDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*flasharray.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "purestorage_host_volume_connection" {
+			continue
+		}
+
+		connection, _ := getHostVolumeConnection(client, rs.Primary.Attributes["host"], rs.Primary.Attributes["volume"])
+		if connection != nil {
+			return fmt.Errorf("connection '%s' still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckPureHostVolumeConnectionExists(n string, exists bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		client := testAccProvider.Meta().(*flasharray.Client)
+		connection, err := getHostVolumeConnection(client, rs.Primary.Attributes["host"], rs.Primary.Attributes["volume"])
+		if err != nil || connection == nil {
+			if exists {
+				return fmt.Errorf("connection does not exist: %s", n)
+			}
+			return nil
+		}
+		return nil
+	}
+}
+
+func testAccCheckPureHostVolumeConnectionConfig(rInt int) string {
+	return fmt.Sprintf(`
+resource "purestorage_volume" "tfconnectiontest-volume" {
+	name = "tfconnectiontest-volume-%d"
+	size = 1024000000
+}
+
+resource "purestorage_host" "tfconnectiontest" {
+	name = "tfconnectiontest%d"
+
+	lifecycle {
+		ignore_changes = ["volume"]
+	}
+}
+
+resource "purestorage_host_volume_connection" "tfconnectiontest" {
+	host   = "${purestorage_host.tfconnectiontest.name}"
+	volume = "${purestorage_volume.tfconnectiontest-volume.name}"
+	lun    = 10
+}`, rInt, rInt)
+}
@@ -0,0 +1,127 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"fmt"
+
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourcePureHostgroupMember() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePureHostgroupMemberCreate,
+		Read:   resourcePureHostgroupMemberRead,
+		Delete: resourcePureHostgroupMemberDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourcePureHostgroupMemberImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"hostgroup": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Name of the hostgroup.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"host": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Name of the host to add to the hostgroup.",
+				Required:    true,
+				ForceNew:    true,
+			},
+		},
+	}
+}
+
+// resourcePureHostgroupMemberCreate adds the host to the hostgroup, leaving
+// the other members of the hostgroup unchanged.
+func resourcePureHostgroupMemberCreate(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	hgroup := d.Get("hostgroup").(string)
+	host := d.Get("host").(string)
+
+	if err := checkHostsHgroup(client, hgroup, []string{host}); err != nil {
+		return err
+	}
+
+	if _, err := client.Hostgroups.SetHostgroup(hgroup, map[string][]string{"addhostlist": {host}}); err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", hgroup, host))
+	return resourcePureHostgroupMemberRead(d, m)
+}
+
+func resourcePureHostgroupMemberRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	hgroup, name, err := splitConnectionID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	host, _ := client.Hosts.GetHost(name, nil)
+
+	if host == nil || host.Hgroup != hgroup {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("hostgroup", host.Hgroup)
+	d.Set("host", host.Name)
+	return nil
+}
+
+// resourcePureHostgroupMemberDelete removes the host from the hostgroup,
+// leaving the other members of the hostgroup unchanged.
+func resourcePureHostgroupMemberDelete(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	if _, err := client.Hostgroups.SetHostgroup(d.Get("hostgroup").(string), map[string][]string{"remhostlist": {d.Get("host").(string)}}); err != nil {
+		return err
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourcePureHostgroupMemberImport imports a membership into Terraform.
+// The ID must be in the form hgroup/host.
+func resourcePureHostgroupMemberImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*flasharray.Client)
+
+	hgroup, name, err := splitConnectionID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	host, err := client.Hosts.GetHost(name, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if host.Hgroup != hgroup {
+		return nil, fmt.Errorf("host %s is not a member of hostgroup %s", name, hgroup)
+	}
+
+	d.Set("hostgroup", host.Hgroup)
+	d.Set("host", host.Name)
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,135 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+const testAccCheckPureHostgroupMemberResourceName = "purestorage_hostgroup_member.tfmembertest-a"
+
+func TestAccResourcePureHostgroupMember_create(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureHostgroupMemberDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureHostgroupMemberConfig(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureHostgroupMemberExists(testAccCheckPureHostgroupMemberResourceName, true),
+					testAccCheckPureHostgroupMemberExists("purestorage_hostgroup_member.tfmembertest-b", true),
+					resource.TestCheckResourceAttr("purestorage_hostgroup.tfmembertest", "hosts.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourcePureHostgroupMember_import(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureHostgroupMemberDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureHostgroupMemberConfig(rInt),
+			},
+			{
+				ResourceName:      testAccCheckPureHostgroupMemberResourceName,
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("tfmembertest%d/tfmembertest-a-%d", rInt, rInt),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckPureHostgroupMemberDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*flasharray.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "purestorage_hostgroup_member" {
+			continue
+		}
+
+		host, _ := client.Hosts.GetHost(rs.Primary.Attributes["host"], nil)
+		if host != nil && host.Hgroup == rs.Primary.Attributes["hostgroup"] {
+			return fmt.Errorf("member '%s' still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckPureHostgroupMemberExists(n string, exists bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		client := testAccProvider.Meta().(*flasharray.Client)
+		host, err := client.Hosts.GetHost(rs.Primary.Attributes["host"], nil)
+		if err != nil || host.Hgroup != rs.Primary.Attributes["hostgroup"] {
+			if exists {
+				return fmt.Errorf("member does not exist: %s", n)
+			}
+			return nil
+		}
+		return nil
+	}
+}
+
+func testAccCheckPureHostgroupMemberConfig(rInt int) string {
+	return fmt.Sprintf(`
+resource "purestorage_hostgroup" "tfmembertest" {
+	name = "tfmembertest%d"
+}
+
+resource "purestorage_host" "tfmembertest-a" {
+	name = "tfmembertest-a-%d"
+}
+
+resource "purestorage_host" "tfmembertest-b" {
+	name = "tfmembertest-b-%d"
+}
+
+resource "purestorage_hostgroup_member" "tfmembertest-a" {
+	hostgroup = "${purestorage_hostgroup.tfmembertest.name}"
+	host      = "${purestorage_host.tfmembertest-a.name}"
+}
+
+resource "purestorage_hostgroup_member" "tfmembertest-b" {
+	hostgroup = "${purestorage_hostgroup.tfmembertest.name}"
+	host      = "${purestorage_host.tfmembertest-b.name}"
+}`, rInt, rInt, rInt)
+}
@@ -0,0 +1,181 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"fmt"
+
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourcePureHostgroupVolumeConnection() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePureHostgroupVolumeConnectionCreate,
+		Read:   resourcePureHostgroupVolumeConnectionRead,
+		Delete: resourcePureHostgroupVolumeConnectionDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourcePureHostgroupVolumeConnectionImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"hostgroup": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Name of the hostgroup.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"volume": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Name of the volume to connect to the hostgroup.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"lun": &schema.Schema{
+				Type:         schema.TypeInt,
+				Description:  "LUN ID of the connection. If not provided, the array assigns the next LUN that is available on every host in the hostgroup.",
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntBetween(0, 16383),
+			},
+		},
+	}
+}
+
+// resourcePureHostgroupVolumeConnectionCreate creates a shared connection
+// between a hostgroup and a volume.  If a LUN is given, it is checked
+// against the connections of every host in the hostgroup first, so a
+// collision is reported with the host that already uses it.
+func resourcePureHostgroupVolumeConnectionCreate(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	hgroup := d.Get("hostgroup").(string)
+	volume := d.Get("volume").(string)
+
+	data := make(map[string]interface{})
+	if lun, ok := d.GetOkExists("lun"); ok {
+		if err := checkHostgroupLunAvailable(client, hgroup, volume, lun.(int)); err != nil {
+			return err
+		}
+		data["lun"] = lun.(int)
+	}
+
+	if _, err := client.Hostgroups.ConnectHostgroup(hgroup, volume, data); err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", hgroup, volume))
+	return resourcePureHostgroupVolumeConnectionRead(d, m)
+}
+
+func resourcePureHostgroupVolumeConnectionRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	hgroup, volume, err := splitConnectionID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	connection, _ := getHostgroupVolumeConnection(client, hgroup, volume)
+
+	if connection == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("hostgroup", connection.Name)
+	d.Set("volume", connection.Vol)
+	d.Set("lun", connection.Lun)
+	return nil
+}
+
+func resourcePureHostgroupVolumeConnectionDelete(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	if _, err := client.Hostgroups.DisconnectHostgroup(d.Get("hostgroup").(string), d.Get("volume").(string)); err != nil {
+		return err
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourcePureHostgroupVolumeConnectionImport imports a connection into
+// Terraform.  The ID must be in the form hgroup/vol.
+func resourcePureHostgroupVolumeConnectionImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*flasharray.Client)
+
+	hgroup, volume, err := splitConnectionID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	connection, err := getHostgroupVolumeConnection(client, hgroup, volume)
+	if err != nil {
+		return nil, err
+	}
+
+	if connection == nil {
+		return nil, fmt.Errorf("volume %s is not connected to hostgroup %s", volume, hgroup)
+	}
+
+	d.Set("hostgroup", connection.Name)
+	d.Set("volume", connection.Vol)
+	d.Set("lun", connection.Lun)
+	return []*schema.ResourceData{d}, nil
+}
+
+// getHostgroupVolumeConnection returns the shared connection between the
+// hostgroup and volume, or nil if they are not connected.
+func getHostgroupVolumeConnection(client *flasharray.Client, hgroup string, volume string) (*flasharray.HostgroupConnection, error) {
+	connections, err := client.Hostgroups.ListHostgroupConnections(hgroup)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range connections {
+		if c.Vol == volume {
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+// checkHostgroupLunAvailable returns an error if the LUN is already used by
+// another volume on any host in the hostgroup, through either a private or
+// a shared connection.
+func checkHostgroupLunAvailable(client *flasharray.Client, hgroup string, volume string, lun int) error {
+	h, err := client.Hostgroups.GetHostgroup(hgroup, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, host := range h.Hosts {
+		connections, err := client.Hosts.ListHostConnections(host, nil)
+		if err != nil {
+			return err
+		}
+		for _, c := range connections {
+			if c.Lun == lun && c.Vol != volume {
+				return fmt.Errorf("LUN %d is already used by volume %s on host %s in hostgroup %s", lun, c.Vol, host, hgroup)
+			}
+		}
+	}
+	return nil
+}
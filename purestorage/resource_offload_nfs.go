@@ -0,0 +1,142 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"fmt"
+
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourcePureOffloadNFS() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePureOffloadNFSCreate,
+		Read:   resourcePureOffloadNFSRead,
+		Delete: resourcePureOffloadNFSDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourcePureOffloadNFSImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Name of the offload target.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"address": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Hostname or IP address of the NFS server.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"mount_point": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Export on the NFS server to mount, such as /export/offload.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"mount_options": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Options to mount the export with, such as vers=3.",
+				Optional:    true,
+				ForceNew:    true,
+			},
+		},
+	}
+}
+
+// resourcePureOffloadNFSCreate connects the array to the NFS offload target.
+// The connection cannot be changed, so every argument forces a new one.
+func resourcePureOffloadNFSCreate(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	offload, err := connectNFSOffload(client, d.Get("name").(string), d.Get("address").(string), d.Get("mount_point").(string), d.Get("mount_options").(string))
+	if err != nil {
+		return err
+	}
+
+	d.SetId(offload.Name)
+	return resourcePureOffloadNFSRead(d, m)
+}
+
+func resourcePureOffloadNFSRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	offload, _ := client.Offloads.GetNFSOffload(d.Id())
+
+	if offload == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", offload.Name)
+	d.Set("address", offload.Address)
+	d.Set("mount_point", offload.MountPoint)
+	d.Set("mount_options", offload.MountOptions)
+	return nil
+}
+
+// resourcePureOffloadNFSDelete disconnects the offload target.  The array
+// refuses to disconnect it while a protection group still replicates to it.
+func resourcePureOffloadNFSDelete(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	if _, err := client.Offloads.DisconnectNFSOffload(d.Id()); err != nil {
+		return err
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourcePureOffloadNFSImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*flasharray.Client)
+
+	offload, err := client.Offloads.GetNFSOffload(d.Id())
+
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("name", offload.Name)
+	d.Set("address", offload.Address)
+	d.Set("mount_point", offload.MountPoint)
+	d.Set("mount_options", offload.MountOptions)
+	return []*schema.ResourceData{d}, nil
+}
+
+// connectNFSOffload connects an NFS offload target.  ConnectNFSOffload does
+// not take mount options, so a connection with mount options is requested
+// directly.
+func connectNFSOffload(client *flasharray.Client, name string, address string, mountPoint string, mountOptions string) (*flasharray.NFSOffload, error) {
+	if mountOptions == "" {
+		return client.Offloads.ConnectNFSOffload(name, address, mountPoint)
+	}
+
+	data := map[string]string{"address": address, "mount_point": mountPoint, "mount_options": mountOptions}
+	req, err := client.NewRequest("POST", fmt.Sprintf("nfs_offload/%s", name), nil, data)
+	if err != nil {
+		return nil, err
+	}
+	offload := &flasharray.NFSOffload{}
+	if _, err = client.Do(req, offload, false); err != nil {
+		return nil, err
+	}
+	return offload, nil
+}
@@ -0,0 +1,122 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+const testAccCheckPureOffloadNFSResourceName = "purestorage_offload_nfs.tfoffloadtest"
+
+// Connect to the NFS export named by PURE_NFS_OFFLOAD_ADDRESS and
+// PURE_NFS_OFFLOAD_MOUNT_POINT, and offload a protection group to it.
+func TestAccResourcePureOffloadNFS_create(t *testing.T) {
+	address := os.Getenv("PURE_NFS_OFFLOAD_ADDRESS")
+	mountPoint := os.Getenv("PURE_NFS_OFFLOAD_MOUNT_POINT")
+	if address == "" || mountPoint == "" {
+		t.Skip("set PURE_NFS_OFFLOAD_ADDRESS and PURE_NFS_OFFLOAD_MOUNT_POINT to test NFS offload targets")
+	}
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureOffloadNFSDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureOffloadNFSConfig(rInt, address, mountPoint),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureOffloadNFSExists(testAccCheckPureOffloadNFSResourceName, true),
+					resource.TestCheckResourceAttr(testAccCheckPureOffloadNFSResourceName, "address", address),
+					resource.TestCheckResourceAttr(testAccCheckPureOffloadNFSResourceName, "mount_point", mountPoint),
+					resource.TestCheckResourceAttr("purestorage_protectiongroup.tfoffloadtest", "target.#", "1"),
+				),
+			},
+			{
+				ResourceName:      testAccCheckPureOffloadNFSResourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckPureOffloadNFSDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*flasharray.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "purestorage_offload_nfs" {
+			continue
+		}
+
+		_, err := client.Offloads.GetNFSOffload(rs.Primary.ID)
+		if err != nil {
+			return nil
+		}
+		return fmt.Errorf("offload target '%s' still exists", rs.Primary.ID)
+	}
+
+	return nil
+}
+
+func testAccCheckPureOffloadNFSExists(n string, exists bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		client := testAccProvider.Meta().(*flasharray.Client)
+		_, err := client.Offloads.GetNFSOffload(rs.Primary.ID)
+		if err != nil {
+			if exists {
+				return fmt.Errorf("offload target does not exist: %s", n)
+			}
+			return nil
+		}
+		return nil
+	}
+}
+
+func testAccCheckPureOffloadNFSConfig(rInt int, address string, mountPoint string) string {
+	return fmt.Sprintf(`
+resource "purestorage_offload_nfs" "tfoffloadtest" {
+	name        = "tfoffloadtest-%d"
+	address     = "%s"
+	mount_point = "%s"
+}
+
+resource "purestorage_protectiongroup" "tfoffloadtest" {
+	name         = "tfoffloadtest-%d"
+	destroy_mode = "eradicate"
+
+	target {
+		name = "${purestorage_offload_nfs.tfoffloadtest.name}"
+	}
+}`, rInt, address, mountPoint, rInt)
+}
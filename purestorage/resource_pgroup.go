@@ -17,10 +17,55 @@
 package purestorage
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+
 	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/hashcode"
 	"github.com/hashicorp/terraform/helper/schema"
 )
 
+// pgroupSchedule is the schedule of a protection group.  The client decodes
+// replicate_blackout as a single window, but the array may return a list of
+// windows, so it is decoded separately.
+type pgroupSchedule struct {
+	flasharray.Protectiongroup
+	ReplicateBlackout json.RawMessage `json:"replicate_blackout,omitempty"`
+}
+
+// blackouts returns the replication blackout windows of the schedule,
+// leaving out empty windows.
+func (s *pgroupSchedule) blackouts() ([]map[string]int, error) {
+	var windows []map[string]int
+	raw := bytes.TrimSpace(s.ReplicateBlackout)
+
+	switch {
+	case len(raw) == 0 || bytes.Equal(raw, []byte("null")):
+		return nil, nil
+	case raw[0] == '[':
+		if err := json.Unmarshal(raw, &windows); err != nil {
+			return nil, err
+		}
+	default:
+		var window map[string]int
+		if err := json.Unmarshal(raw, &window); err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+
+	var out []map[string]int
+	for _, w := range windows {
+		if w["start"] != w["end"] {
+			out = append(out, w)
+		}
+	}
+	return out, nil
+}
+
 func resourcePureProtectiongroup() *schema.Resource {
 	return &schema.Resource{
 		Create: resourcePureProtectiongroupCreate,
@@ -30,6 +75,7 @@ func resourcePureProtectiongroup() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: resourcePureProtectiongroupImport,
 		},
+		CustomizeDiff: resourcePureProtectiongroupCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
@@ -63,13 +109,24 @@ func resourcePureProtectiongroup() *schema.Resource {
 				Optional:      true,
 				Default:       nil,
 			},
-			"targets": &schema.Schema{
-				Type: schema.TypeList,
-				Elem: &schema.Schema{
-					Type: schema.TypeMap,
+			"target": &schema.Schema{
+				Type:        schema.TypeSet,
+				Description: "Arrays or offload targets the protection group replicates to.",
+				Optional:    true,
+				Set:         resourcePgroupTargetHash,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"allowed": &schema.Schema{
+							Type:        schema.TypeBool,
+							Description: "Whether the target allows the protection group to replicate to it.",
+							Computed:    true,
+						},
+					},
 				},
-				Optional: true,
-				Default:  nil,
 			},
 			"source": &schema.Schema{
 				Type:     schema.TypeString,
@@ -77,10 +134,12 @@ func resourcePureProtectiongroup() *schema.Resource {
 				Computed: true,
 			},
 			"all_for": &schema.Schema{
-				Type:        schema.TypeInt,
-				Description: "Modifies the retention policy of the protection group. Specifies the length of time to keep the snapshots on the source array before they are eradicated.",
-				Optional:    true,
-				Default:     86400,
+				Type:         schema.TypeString,
+				Description:  "Modifies the retention policy of the protection group. Specifies the length of time to keep the snapshots on the source array before they are eradicated, such as 1d.",
+				Optional:     true,
+				Default:      "1d",
+				ValidateFunc: validateDuration,
+				StateFunc:    normalizeDuration,
 			},
 			"days": &schema.Schema{
 				Type:        schema.TypeInt,
@@ -95,16 +154,36 @@ func resourcePureProtectiongroup() *schema.Resource {
 				Default:     4,
 			},
 			"replicate_at": &schema.Schema{
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Description: "Modifies the replication schedule of the protection group. Specifies the preferred time, on the hour, at which to replicate the snapshots.",
-				Default:     nil,
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "Modifies the replication schedule of the protection group. Specifies the preferred time, on the hour, at which to replicate the snapshots, such as 02:00.",
+				ValidateFunc:     validateTimeOfDay,
+				StateFunc:        normalizeTimeOfDay,
+				DiffSuppressFunc: suppressUnsetTimeOfDay,
 			},
-			"replicate_blackout": &schema.Schema{
-				Type:        schema.TypeMap,
-				Description: "Modifies the replication schedule of the protection group. Specifies the range of time at which to suspend replication.",
+			"blackout": &schema.Schema{
+				Type:        schema.TypeSet,
+				Description: "Modifies the replication schedule of the protection group. Specifies a range of time at which to suspend replication.",
 				Optional:    true,
-				Default:     nil,
+				Set:         resourcePgroupBlackoutHash,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"start": &schema.Schema{
+							Type:         schema.TypeString,
+							Description:  "Time of day the blackout starts, such as 08:00.",
+							Required:     true,
+							ValidateFunc: validateTimeOfDay,
+							StateFunc:    normalizeTimeOfDay,
+						},
+						"end": &schema.Schema{
+							Type:         schema.TypeString,
+							Description:  "Time of day the blackout ends, such as 18:00. A blackout that ends before it starts continues past midnight.",
+							Required:     true,
+							ValidateFunc: validateTimeOfDay,
+							StateFunc:    normalizeTimeOfDay,
+						},
+					},
+				},
 			},
 			"replicate_enabled": &schema.Schema{
 				Type:        schema.TypeBool,
@@ -113,16 +192,20 @@ func resourcePureProtectiongroup() *schema.Resource {
 				Default:     false,
 			},
 			"replicate_frequency": &schema.Schema{
-				Type:        schema.TypeInt,
-				Description: "Modifies the replication schedule of the protection group. Specifies the replication frequency.",
-				Optional:    true,
-				Default:     14400,
+				Type:         schema.TypeString,
+				Description:  "Modifies the replication schedule of the protection group. Specifies the replication frequency, such as 4h.",
+				Optional:     true,
+				Default:      "4h",
+				ValidateFunc: validateDuration,
+				StateFunc:    normalizeDuration,
 			},
 			"snap_at": &schema.Schema{
-				Type:        schema.TypeInt,
-				Description: "Modifies the snapshot schedule of the protection group. Specifies the preferred time, on the hour, at which to generate the snapshot.",
-				Optional:    true,
-				Default:     nil,
+				Type:             schema.TypeString,
+				Description:      "Modifies the snapshot schedule of the protection group. Specifies the preferred time, on the hour, at which to generate the snapshot, such as 02:00.",
+				Optional:         true,
+				ValidateFunc:     validateTimeOfDay,
+				StateFunc:        normalizeTimeOfDay,
+				DiffSuppressFunc: suppressUnsetTimeOfDay,
 			},
 			"snap_enabled": &schema.Schema{
 				Type:        schema.TypeBool,
@@ -131,16 +214,20 @@ func resourcePureProtectiongroup() *schema.Resource {
 				Default:     false,
 			},
 			"snap_frequency": &schema.Schema{
-				Type:        schema.TypeInt,
-				Description: "Modifies the snapshot schedule of the protection group. Specifies the snapshot frequency.",
-				Optional:    true,
-				Default:     3600,
+				Type:         schema.TypeString,
+				Description:  "Modifies the snapshot schedule of the protection group. Specifies the snapshot frequency, such as 1h.",
+				Optional:     true,
+				Default:      "1h",
+				ValidateFunc: validateDuration,
+				StateFunc:    normalizeDuration,
 			},
 			"target_all_for": &schema.Schema{
-				Type:        schema.TypeInt,
-				Description: "Modifies the retention policy of the protection group. Specifies the length of time to keep the replicated snapshots on the targets.",
-				Optional:    true,
-				Default:     86400,
+				Type:         schema.TypeString,
+				Description:  "Modifies the retention policy of the protection group. Specifies the length of time to keep the replicated snapshots on the targets, such as 1d.",
+				Optional:     true,
+				Default:      "1d",
+				ValidateFunc: validateDuration,
+				StateFunc:    normalizeDuration,
 			},
 			"target_days": &schema.Schema{
 				Type:        schema.TypeInt,
@@ -154,6 +241,8 @@ func resourcePureProtectiongroup() *schema.Resource {
 				Optional:    true,
 				Default:     4,
 			},
+			"destroy_mode":         schemaDestroyMode(),
+			"recover_if_destroyed": schemaRecoverIfDestroyed(),
 		},
 	}
 }
@@ -165,6 +254,11 @@ func resourcePureProtectiongroupCreate(d *schema.ResourceData, m interface{}) er
 	var pgroup *flasharray.Protectiongroup
 	var err error
 
+	blackouts, err := expandPgroupBlackouts(d.Get("blackout").(*schema.Set))
+	if err != nil {
+		return err
+	}
+
 	data := make(map[string]interface{})
 
 	if h, ok := d.GetOk("hosts"); ok {
@@ -191,27 +285,39 @@ func resourcePureProtectiongroupCreate(d *schema.ResourceData, m interface{}) er
 		data["hgrouplist"] = hgroups
 	}
 
-	if t, ok := d.GetOk("targets"); ok {
-		var targets []string
-		for _, element := range t.([]interface{}) {
-			targets = append(targets, element.(string))
+	if t, ok := d.GetOk("target"); ok {
+		data["targetlist"] = expandPgroupTargets(t.(*schema.Set))
+	}
+
+	if d.Get("recover_if_destroyed").(bool) {
+		if pgroup, err = recoverDestroyedProtectiongroup(client, d.Get("name").(string)); err != nil {
+			return err
+		}
+		if pgroup != nil {
+			log.Printf("[INFO] Recovered destroyed protection group %s.", pgroup.Name)
+			if len(data) > 0 {
+				if pgroup, err = client.Protectiongroups.SetProtectiongroup(pgroup.Name, data); err != nil {
+					return err
+				}
+			}
 		}
-		data["targetlist"] = targets
 	}
 
-	if pgroup, err = client.Protectiongroups.CreateProtectiongroup(d.Get("name").(string), data); err != nil {
-		return err
+	if pgroup == nil {
+		if pgroup, err = client.Protectiongroups.CreateProtectiongroup(d.Get("name").(string), data); err != nil {
+			return err
+		}
 	}
 	d.SetId(pgroup.Name)
 	d.SetPartial("name")
 	d.SetPartial("hosts")
 	d.SetPartial("volumes")
 	d.SetPartial("hgroups")
-	d.SetPartial("targets")
+	d.SetPartial("target")
 
 	retentionData := make(map[string]interface{})
 	if allFor, ok := d.GetOk("all_for"); ok {
-		retentionData["all_for"] = allFor
+		retentionData["all_for"], _ = parseDuration(allFor.(string))
 	}
 
 	if days, ok := d.GetOk("days"); ok {
@@ -223,7 +329,7 @@ func resourcePureProtectiongroupCreate(d *schema.ResourceData, m interface{}) er
 	}
 
 	if targetAllFor, ok := d.GetOk("target_all_for"); ok {
-		retentionData["target_all_for"] = targetAllFor
+		retentionData["target_all_for"], _ = parseDuration(targetAllFor.(string))
 	}
 
 	if targetDays, ok := d.GetOk("target_days"); ok {
@@ -247,30 +353,30 @@ func resourcePureProtectiongroupCreate(d *schema.ResourceData, m interface{}) er
 	scheduleData := make(map[string]interface{})
 
 	if replicateAt, ok := d.GetOk("replicate_at"); ok {
-		scheduleData["replicate_at"] = replicateAt
+		scheduleData["replicate_at"], _ = parseTimeOfDay(replicateAt.(string))
 	}
 
-	if replicateBlackout, ok := d.GetOk("replicate_blackout"); ok {
-		scheduleData["replicate_blackout"] = replicateBlackout
+	if len(blackouts) > 0 {
+		scheduleData["replicate_blackout"] = blackouts
 	}
 
 	if replicateFrequency, ok := d.GetOk("replicate_frequency"); ok {
-		scheduleData["replicate_frequency"] = replicateFrequency
+		scheduleData["replicate_frequency"], _ = parseDuration(replicateFrequency.(string))
 	}
 
 	if snapAt, ok := d.GetOk("snap_at"); ok {
-		scheduleData["snap_at"] = snapAt
+		scheduleData["snap_at"], _ = parseTimeOfDay(snapAt.(string))
 	}
 
 	if snapFrequency, ok := d.GetOk("snap_frequency"); ok {
-		scheduleData["snap_frequency"] = snapFrequency
+		scheduleData["snap_frequency"], _ = parseDuration(snapFrequency.(string))
 	}
 
 	if _, err = client.Protectiongroups.SetProtectiongroup(d.Id(), scheduleData); err != nil {
 		return err
 	}
 	d.SetPartial("replicate_at")
-	d.SetPartial("replicate_blackout")
+	d.SetPartial("blackout")
 	d.SetPartial("replicate_frequency")
 	d.SetPartial("snap_at")
 	d.SetPartial("snap_frequency")
@@ -319,27 +425,34 @@ func resourcePureProtectiongroupRead(d *schema.ResourceData, m interface{}) erro
 	d.Set("volumes", p.Volumes)
 	d.Set("hgroups", p.Hgroups)
 	d.Set("source", p.Source)
-	d.Set("targets", p.Targets)
+	if err := d.Set("target", flattenPgroupTargets(p.Targets)); err != nil {
+		return err
+	}
 
-	params := map[string]string{"schedule": "true"}
-	s, _ := client.Protectiongroups.GetProtectiongroup(d.Id(), params)
+	s, _ := getPgroupSchedule(client, d.Id())
 	if s != nil {
-		d.Set("replicate_at", s.ReplicateAt)
-		d.Set("replicate_blackout", s.ReplicateBlackout)
-		d.Set("replicate_frequency", s.ReplicateFrequency)
+		blackouts, err := s.blackouts()
+		if err != nil {
+			return err
+		}
+		d.Set("replicate_at", strconv.Itoa(s.ReplicateAt))
+		if err := d.Set("blackout", flattenPgroupBlackouts(blackouts)); err != nil {
+			return err
+		}
+		d.Set("replicate_frequency", strconv.Itoa(s.ReplicateFrequency))
 		d.Set("replicate_enabled", s.ReplicateEnabled)
-		d.Set("snap_at", s.SnapAt)
+		d.Set("snap_at", strconv.Itoa(s.SnapAt))
 		d.Set("snap_enabled", s.SnapEnabled)
-		d.Set("snap_frequency", s.SnapFrequency)
+		d.Set("snap_frequency", strconv.Itoa(s.SnapFrequency))
 	}
 
-	params = map[string]string{"retention": "true"}
+	params := map[string]string{"retention": "true"}
 	r, _ := client.Protectiongroups.GetProtectiongroup(d.Id(), params)
 	if r != nil {
-		d.Set("all_for", r.Allfor)
+		d.Set("all_for", strconv.Itoa(r.Allfor))
 		d.Set("days", r.Days)
 		d.Set("per_day", r.Perday)
-		d.Set("target_all_for", r.TargetAllfor)
+		d.Set("target_all_for", strconv.Itoa(r.TargetAllfor))
 		d.Set("target_days", r.TargetDays)
 		d.Set("target_per_day", r.TargetPerDay)
 	}
@@ -386,12 +499,8 @@ func resourcePureProtectiongroupUpdate(d *schema.ResourceData, m interface{}) er
 		data["hgrouplist"] = hgroups
 	}
 
-	if d.HasChange("targets") {
-		var targets []string
-		for _, element := range d.Get("targets").([]interface{}) {
-			targets = append(targets, element.(string))
-		}
-		data["targetlist"] = targets
+	if d.HasChange("target") {
+		data["targetlist"] = expandPgroupTargets(d.Get("target").(*schema.Set))
 	}
 
 	if len(data) > 0 {
@@ -402,11 +511,11 @@ func resourcePureProtectiongroupUpdate(d *schema.ResourceData, m interface{}) er
 	d.SetPartial("hosts")
 	d.SetPartial("volumes")
 	d.SetPartial("hgroups")
-	d.SetPartial("targets")
+	d.SetPartial("target")
 
 	retentionData := make(map[string]interface{})
 	if d.HasChange("all_for") {
-		retentionData["all_for"] = d.Get("all_for").(int)
+		retentionData["all_for"], _ = parseDuration(d.Get("all_for").(string))
 	}
 
 	if d.HasChange("days") {
@@ -418,7 +527,7 @@ func resourcePureProtectiongroupUpdate(d *schema.ResourceData, m interface{}) er
 	}
 
 	if d.HasChange("target_all_for") {
-		retentionData["target_all_for"] = d.Get("target_all_for").(int)
+		retentionData["target_all_for"], _ = parseDuration(d.Get("target_all_for").(string))
 	}
 
 	if d.HasChange("target_days") {
@@ -444,23 +553,27 @@ func resourcePureProtectiongroupUpdate(d *schema.ResourceData, m interface{}) er
 	scheduleData := make(map[string]interface{})
 
 	if d.HasChange("replicate_at") {
-		scheduleData["replicate_at"] = d.Get("replicate_at").(int)
+		scheduleData["replicate_at"], _ = parseTimeOfDay(d.Get("replicate_at").(string))
 	}
 
-	if d.HasChange("replicate_blackout") {
-		scheduleData["replicate_blackout"] = d.Get("replicate_blackout").([]interface{})
+	if d.HasChange("blackout") {
+		blackouts, err := expandPgroupBlackouts(d.Get("blackout").(*schema.Set))
+		if err != nil {
+			return err
+		}
+		scheduleData["replicate_blackout"] = blackouts
 	}
 
 	if d.HasChange("replicate_frequency") {
-		scheduleData["replicate_frequency"] = d.Get("replicate_frequency").(int)
+		scheduleData["replicate_frequency"], _ = parseDuration(d.Get("replicate_frequency").(string))
 	}
 
 	if d.HasChange("snap_at") {
-		scheduleData["snap_at"] = d.Get("snap_at").(int)
+		scheduleData["snap_at"], _ = parseTimeOfDay(d.Get("snap_at").(string))
 	}
 
 	if d.HasChange("snap_frequency") {
-		scheduleData["snap_frequency"] = d.Get("snap_frequency").(int)
+		scheduleData["snap_frequency"], _ = parseDuration(d.Get("snap_frequency").(string))
 	}
 
 	if len(scheduleData) > 0 {
@@ -469,7 +582,7 @@ func resourcePureProtectiongroupUpdate(d *schema.ResourceData, m interface{}) er
 		}
 	}
 	d.SetPartial("replicate_at")
-	d.SetPartial("replicate_blackout")
+	d.SetPartial("blackout")
 	d.SetPartial("replicate_frequency")
 	d.SetPartial("snap_at")
 	d.SetPartial("snap_frequency")
@@ -511,6 +624,12 @@ func resourcePureProtectiongroupDelete(d *schema.ResourceData, m interface{}) er
 		return err
 	}
 
+	if d.Get("destroy_mode").(string) == destroyModeEradicate {
+		if _, err = client.Protectiongroups.EradicateProtectiongroup(d.Id()); err != nil {
+			return err
+		}
+	}
+
 	d.SetId("")
 	return nil
 }
@@ -529,29 +648,133 @@ func resourcePureProtectiongroupImport(d *schema.ResourceData, m interface{}) ([
 	d.Set("volumes", p.Volumes)
 	d.Set("hgroups", p.Hgroups)
 	d.Set("source", p.Source)
-	d.Set("targets", p.Targets)
+	if err := d.Set("target", flattenPgroupTargets(p.Targets)); err != nil {
+		return nil, err
+	}
 
-	params := map[string]string{"schedule": "true"}
-	s, _ := client.Protectiongroups.GetProtectiongroup(d.Id(), params)
+	s, _ := getPgroupSchedule(client, d.Id())
 	if s != nil {
-		d.Set("replicate_at", s.ReplicateAt)
-		d.Set("replicate_blackout", s.ReplicateBlackout)
-		d.Set("replicate_frequency", s.ReplicateFrequency)
+		blackouts, err := s.blackouts()
+		if err != nil {
+			return nil, err
+		}
+		d.Set("replicate_at", strconv.Itoa(s.ReplicateAt))
+		if err := d.Set("blackout", flattenPgroupBlackouts(blackouts)); err != nil {
+			return nil, err
+		}
+		d.Set("replicate_frequency", strconv.Itoa(s.ReplicateFrequency))
 		d.Set("replicate_enabled", s.ReplicateEnabled)
-		d.Set("snap_at", s.SnapAt)
+		d.Set("snap_at", strconv.Itoa(s.SnapAt))
 		d.Set("snap_enabled", s.SnapEnabled)
-		d.Set("snap_frequency", s.SnapFrequency)
+		d.Set("snap_frequency", strconv.Itoa(s.SnapFrequency))
 	}
 
-	params = map[string]string{"retention": "true"}
+	params := map[string]string{"retention": "true"}
 	r, _ := client.Protectiongroups.GetProtectiongroup(d.Id(), params)
 	if r != nil {
-		d.Set("all_for", r.Allfor)
+		d.Set("all_for", strconv.Itoa(r.Allfor))
 		d.Set("days", r.Days)
 		d.Set("per_day", r.Perday)
-		d.Set("target_all_for", r.TargetAllfor)
+		d.Set("target_all_for", strconv.Itoa(r.TargetAllfor))
 		d.Set("target_days", r.TargetDays)
 		d.Set("target_per_day", r.TargetPerDay)
 	}
+	d.Set("destroy_mode", destroyModeDestroy)
+	d.Set("recover_if_destroyed", false)
 	return []*schema.ResourceData{d}, nil
 }
+
+// recoverDestroyedProtectiongroup recovers the named protection group if it is pending eradication,
+// see isPendingEradication.  nil is returned if there is no such protection group.
+func recoverDestroyedProtectiongroup(client *flasharray.Client, name string) (*flasharray.Protectiongroup, error) {
+	pending, err := isPendingEradication(client, "pgroup", name)
+	if err != nil || !pending {
+		return nil, err
+	}
+
+	if _, err = client.Protectiongroups.RecoverProtectiongroup(name); err != nil {
+		return nil, err
+	}
+	return client.Protectiongroups.GetProtectiongroup(name, nil)
+}
+
+// resourcePgroupTargetHash hashes a target by its name only, so a change
+// to allowed on the target array does not replace the target.
+func resourcePgroupTargetHash(v interface{}) int {
+	return hashcode.String(v.(map[string]interface{})["name"].(string))
+}
+
+// expandPgroupTargets returns the names of the targets in the target set.
+func expandPgroupTargets(set *schema.Set) []string {
+	targets := []string{}
+	for _, t := range set.List() {
+		targets = append(targets, t.(map[string]interface{})["name"].(string))
+	}
+	return targets
+}
+
+// getPgroupSchedule returns the snapshot and replication schedule of a
+// protection group.
+func getPgroupSchedule(client *flasharray.Client, name string) (*pgroupSchedule, error) {
+	req, err := client.NewRequest("GET", fmt.Sprintf("pgroup/%s", name), map[string]string{"schedule": "true"}, nil)
+	if err != nil {
+		return nil, err
+	}
+	s := &pgroupSchedule{}
+	if _, err = client.Do(req, s, false); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// resourcePgroupBlackoutHash hashes a blackout window by its start and end
+// in seconds, so a time written as 08:00 matches the 28800 read back from
+// the array.
+func resourcePgroupBlackoutHash(v interface{}) int {
+	m := v.(map[string]interface{})
+	return hashcode.String(fmt.Sprintf("%s-%s", normalizeTimeOfDay(m["start"].(string)), normalizeTimeOfDay(m["end"].(string))))
+}
+
+// resourcePureProtectiongroupCustomizeDiff rejects blackouts that overlap
+// or are empty when the plan is created, rather than part way through an
+// update.
+func resourcePureProtectiongroupCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("blackout") {
+		return nil
+	}
+	_, err := expandPgroupBlackouts(d.Get("blackout").(*schema.Set))
+	return err
+}
+
+// expandPgroupBlackouts returns the blackout windows in seconds after
+// midnight.  A window must not be empty, and windows must not overlap, so
+// that together they fit in a day.
+func expandPgroupBlackouts(set *schema.Set) ([]map[string]int, error) {
+	blackouts := []map[string]int{}
+	var hours [24]bool
+
+	for _, b := range set.List() {
+		m := b.(map[string]interface{})
+		start, err := parseTimeOfDay(m["start"].(string))
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseTimeOfDay(m["end"].(string))
+		if err != nil {
+			return nil, err
+		}
+		if start == end {
+			return nil, fmt.Errorf("blackout starting at %s must end at a different time", m["start"].(string))
+		}
+
+		for h := start / 3600; h != end/3600; h = (h + 1) % 24 {
+			if hours[h] {
+				return nil, fmt.Errorf("blackout from %s to %s overlaps another blackout", m["start"].(string), m["end"].(string))
+			}
+			hours[h] = true
+		}
+
+		blackouts = append(blackouts, map[string]int{"start": start, "end": end})
+	}
+	return blackouts, nil
+}
@@ -0,0 +1,185 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// resourcePureProtectiongroupAllow manages whether a replication target
+// allows a protection group to replicate to it.  It runs against the target
+// array, where the protection group is named source_array:protection_group.
+func resourcePureProtectiongroupAllow() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePureProtectiongroupAllowCreate,
+		Read:   resourcePureProtectiongroupAllowRead,
+		Update: resourcePureProtectiongroupAllowUpdate,
+		Delete: resourcePureProtectiongroupAllowDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourcePureProtectiongroupAllowImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"source_array": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Name of the array the protection group replicates from.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"protection_group": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Name of the protection group on the source array.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"allowed": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: "Allow the protection group to replicate to this array.",
+				Optional:    true,
+				Default:     true,
+			},
+		},
+	}
+}
+
+func resourcePureProtectiongroupAllowCreate(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	name := fmt.Sprintf("%s:%s", d.Get("source_array").(string), d.Get("protection_group").(string))
+	if err := setPgroupAllowed(client, name, d.Get("allowed").(bool)); err != nil {
+		return err
+	}
+
+	d.SetId(name)
+	return resourcePureProtectiongroupAllowRead(d, m)
+}
+
+func resourcePureProtectiongroupAllowRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	p, _ := client.Protectiongroups.GetProtectiongroup(d.Id(), nil)
+
+	if p == nil {
+		d.SetId("")
+		return nil
+	}
+
+	allowed, err := getPgroupAllowed(client, p)
+	if err != nil {
+		return err
+	}
+
+	source, pgroup, _ := splitReplicatedPgroupName(p.Name)
+
+	d.Set("source_array", source)
+	d.Set("protection_group", pgroup)
+	d.Set("allowed", allowed)
+	return nil
+}
+
+func resourcePureProtectiongroupAllowUpdate(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	if d.HasChange("allowed") {
+		if err := setPgroupAllowed(client, d.Id(), d.Get("allowed").(bool)); err != nil {
+			return err
+		}
+	}
+
+	return resourcePureProtectiongroupAllowRead(d, m)
+}
+
+// resourcePureProtectiongroupAllowDelete disallows the protection group.
+// The replicated protection group itself belongs to the source array, and is
+// removed when the source array stops replicating to this array.
+func resourcePureProtectiongroupAllowDelete(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	p, _ := client.Protectiongroups.GetProtectiongroup(d.Id(), nil)
+
+	if p != nil {
+		if err := setPgroupAllowed(client, d.Id(), false); err != nil {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourcePureProtectiongroupAllowImport imports the allowed state of a
+// replicated protection group.  The ID must be in the form
+// source_array:protection_group.
+func resourcePureProtectiongroupAllowImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*flasharray.Client)
+
+	if _, _, err := splitReplicatedPgroupName(d.Id()); err != nil {
+		return nil, err
+	}
+
+	p, err := client.Protectiongroups.GetProtectiongroup(d.Id(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, err := getPgroupAllowed(client, p)
+	if err != nil {
+		return nil, err
+	}
+
+	source, pgroup, _ := splitReplicatedPgroupName(p.Name)
+
+	d.Set("source_array", source)
+	d.Set("protection_group", pgroup)
+	d.Set("allowed", allowed)
+	return []*schema.ResourceData{d}, nil
+}
+
+// getPgroupAllowed returns whether the local array allows the replicated
+// protection group, from the target list of the protection group.
+func getPgroupAllowed(client *flasharray.Client, p *flasharray.Protectiongroup) (bool, error) {
+	local, err := client.Array.Get(nil)
+	if err != nil {
+		return false, err
+	}
+
+	for _, t := range flattenPgroupTargets(p.Targets) {
+		if t["name"].(string) == local.ArrayName {
+			return t["allowed"].(bool), nil
+		}
+	}
+	return false, nil
+}
+
+func setPgroupAllowed(client *flasharray.Client, name string, allowed bool) error {
+	_, err := client.Protectiongroups.SetProtectiongroup(name, map[string]bool{"allowed": allowed})
+	return err
+}
+
+// splitReplicatedPgroupName splits the name of a replicated protection group
+// into the source array and the protection group name.
+func splitReplicatedPgroupName(name string) (string, string, error) {
+	parts := strings.SplitN(name, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid protection group name %q, expected source_array:protection_group", name)
+	}
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,37 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"testing"
+)
+
+func Test_splitReplicatedPgroupName(t *testing.T) {
+	source, pgroup, err := splitReplicatedPgroupName("array1:pod::pgroup")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if source != "array1" || pgroup != "pod::pgroup" {
+		t.Fatalf("Wrong values returned: %s, %s", source, pgroup)
+	}
+
+	for _, name := range []string{"pgroup", ":pgroup", "array1:"} {
+		if _, _, err := splitReplicatedPgroupName(name); err == nil {
+			t.Fatalf("Expected error for %s", name)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"fmt"
+
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// pgroupMember describes one kind of protection group member.  The
+// membership resources for volumes, hosts and hostgroups only differ in
+// the name of the member argument and the client calls they use.
+type pgroupMember struct {
+	key     string
+	add     func(client *flasharray.Client, member string, pgroup string) error
+	remove  func(client *flasharray.Client, member string, pgroup string) error
+	members func(pgroup *flasharray.Protectiongroup) []string
+}
+
+var pgroupVolumeMember = pgroupMember{
+	key: "volume",
+	add: func(client *flasharray.Client, member string, pgroup string) error {
+		_, err := client.Volumes.AddVolume(member, pgroup)
+		return err
+	},
+	remove: func(client *flasharray.Client, member string, pgroup string) error {
+		_, err := client.Volumes.RemoveVolume(member, pgroup)
+		return err
+	},
+	members: func(pgroup *flasharray.Protectiongroup) []string {
+		return pgroup.Volumes
+	},
+}
+
+var pgroupHostMember = pgroupMember{
+	key: "host",
+	add: func(client *flasharray.Client, member string, pgroup string) error {
+		_, err := client.Hosts.AddHost(member, pgroup)
+		return err
+	},
+	remove: func(client *flasharray.Client, member string, pgroup string) error {
+		_, err := client.Hosts.RemoveHost(member, pgroup)
+		return err
+	},
+	members: func(pgroup *flasharray.Protectiongroup) []string {
+		return pgroup.Hosts
+	},
+}
+
+var pgroupHostgroupMember = pgroupMember{
+	key: "hostgroup",
+	add: func(client *flasharray.Client, member string, pgroup string) error {
+		_, err := client.Hostgroups.AddHostgroup(member, pgroup)
+		return err
+	},
+	remove: func(client *flasharray.Client, member string, pgroup string) error {
+		_, err := client.Hostgroups.RemoveHostgroup(member, pgroup)
+		return err
+	},
+	members: func(pgroup *flasharray.Protectiongroup) []string {
+		return pgroup.Hgroups
+	},
+}
+
+func resourcePureProtectiongroupVolume() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePureProtectiongroupVolumeCreate,
+		Read:   resourcePureProtectiongroupVolumeRead,
+		Delete: resourcePureProtectiongroupVolumeDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourcePureProtectiongroupVolumeImport,
+		},
+
+		Schema: schemaPgroupMember(pgroupVolumeMember),
+	}
+}
+
+func resourcePureProtectiongroupVolumeCreate(d *schema.ResourceData, m interface{}) error {
+	return resourcePureProtectiongroupMemberCreate(d, m, pgroupVolumeMember)
+}
+
+func resourcePureProtectiongroupVolumeRead(d *schema.ResourceData, m interface{}) error {
+	return resourcePureProtectiongroupMemberRead(d, m, pgroupVolumeMember)
+}
+
+func resourcePureProtectiongroupVolumeDelete(d *schema.ResourceData, m interface{}) error {
+	return resourcePureProtectiongroupMemberDelete(d, m, pgroupVolumeMember)
+}
+
+func resourcePureProtectiongroupVolumeImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	return resourcePureProtectiongroupMemberImport(d, m, pgroupVolumeMember)
+}
+
+func resourcePureProtectiongroupHost() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePureProtectiongroupHostCreate,
+		Read:   resourcePureProtectiongroupHostRead,
+		Delete: resourcePureProtectiongroupHostDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourcePureProtectiongroupHostImport,
+		},
+
+		Schema: schemaPgroupMember(pgroupHostMember),
+	}
+}
+
+func resourcePureProtectiongroupHostCreate(d *schema.ResourceData, m interface{}) error {
+	return resourcePureProtectiongroupMemberCreate(d, m, pgroupHostMember)
+}
+
+func resourcePureProtectiongroupHostRead(d *schema.ResourceData, m interface{}) error {
+	return resourcePureProtectiongroupMemberRead(d, m, pgroupHostMember)
+}
+
+func resourcePureProtectiongroupHostDelete(d *schema.ResourceData, m interface{}) error {
+	return resourcePureProtectiongroupMemberDelete(d, m, pgroupHostMember)
+}
+
+func resourcePureProtectiongroupHostImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	return resourcePureProtectiongroupMemberImport(d, m, pgroupHostMember)
+}
+
+func resourcePureProtectiongroupHostgroup() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePureProtectiongroupHostgroupCreate,
+		Read:   resourcePureProtectiongroupHostgroupRead,
+		Delete: resourcePureProtectiongroupHostgroupDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourcePureProtectiongroupHostgroupImport,
+		},
+
+		Schema: schemaPgroupMember(pgroupHostgroupMember),
+	}
+}
+
+func resourcePureProtectiongroupHostgroupCreate(d *schema.ResourceData, m interface{}) error {
+	return resourcePureProtectiongroupMemberCreate(d, m, pgroupHostgroupMember)
+}
+
+func resourcePureProtectiongroupHostgroupRead(d *schema.ResourceData, m interface{}) error {
+	return resourcePureProtectiongroupMemberRead(d, m, pgroupHostgroupMember)
+}
+
+func resourcePureProtectiongroupHostgroupDelete(d *schema.ResourceData, m interface{}) error {
+	return resourcePureProtectiongroupMemberDelete(d, m, pgroupHostgroupMember)
+}
+
+func resourcePureProtectiongroupHostgroupImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	return resourcePureProtectiongroupMemberImport(d, m, pgroupHostgroupMember)
+}
+
+// schemaPgroupMember returns the schema of a resource that adds a single
+// member to a protection group, leaving the other members unchanged.  The
+// ID of the resource is in the form pgroup/member.
+func schemaPgroupMember(member pgroupMember) map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"protection_group": &schema.Schema{
+			Type:        schema.TypeString,
+			Description: "Name of the protection group.",
+			Required:    true,
+			ForceNew:    true,
+		},
+		member.key: &schema.Schema{
+			Type:        schema.TypeString,
+			Description: fmt.Sprintf("Name of the %s to add to the protection group.", member.key),
+			Required:    true,
+			ForceNew:    true,
+		},
+	}
+}
+
+func resourcePureProtectiongroupMemberCreate(d *schema.ResourceData, m interface{}, member pgroupMember) error {
+	client := m.(*flasharray.Client)
+
+	pgroup := d.Get("protection_group").(string)
+	name := d.Get(member.key).(string)
+	if err := member.add(client, name, pgroup); err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", pgroup, name))
+	return resourcePureProtectiongroupMemberRead(d, m, member)
+}
+
+func resourcePureProtectiongroupMemberRead(d *schema.ResourceData, m interface{}, member pgroupMember) error {
+	client := m.(*flasharray.Client)
+
+	pgroup, name, err := splitConnectionID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	p, _ := client.Protectiongroups.GetProtectiongroup(pgroup, nil)
+
+	if p == nil || !stringInSlice(name, member.members(p)) {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("protection_group", p.Name)
+	d.Set(member.key, name)
+	return nil
+}
+
+func resourcePureProtectiongroupMemberDelete(d *schema.ResourceData, m interface{}, member pgroupMember) error {
+	client := m.(*flasharray.Client)
+
+	if err := member.remove(client, d.Get(member.key).(string), d.Get("protection_group").(string)); err != nil {
+		return err
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourcePureProtectiongroupMemberImport(d *schema.ResourceData, m interface{}, member pgroupMember) ([]*schema.ResourceData, error) {
+	pgroup, name, err := splitConnectionID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := resourcePureProtectiongroupMemberRead(d, m, member); err != nil {
+		return nil, err
+	}
+
+	if d.Id() == "" {
+		return nil, fmt.Errorf("%s %s is not a member of protection group %s", member.key, name, pgroup)
+	}
+	return []*schema.ResourceData{d}, nil
+}
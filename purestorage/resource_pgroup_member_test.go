@@ -0,0 +1,210 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccResourcePureProtectiongroupVolume_create(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureProtectiongroupMemberDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureProtectiongroupVolumeConfig(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureProtectiongroupMemberExists("purestorage_protectiongroup_volume.a", "volume", true),
+					testAccCheckPureProtectiongroupMemberExists("purestorage_protectiongroup_volume.b", "volume", true),
+				),
+			},
+			{
+				ResourceName:      "purestorage_protectiongroup_volume.a",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("tfpgroupmembertest-%d/tfpgroupmembertest-a-%d", rInt, rInt),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccResourcePureProtectiongroupHost_create(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureProtectiongroupMemberDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureProtectiongroupHostConfig(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureProtectiongroupMemberExists("purestorage_protectiongroup_host.a", "host", true),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourcePureProtectiongroupHostgroup_create(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureProtectiongroupMemberDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureProtectiongroupHostgroupConfig(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureProtectiongroupMemberExists("purestorage_protectiongroup_hostgroup.a", "hostgroup", true),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckPureProtectiongroupMemberDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*flasharray.Client)
+
+	members := map[string]pgroupMember{
+		"purestorage_protectiongroup_volume":    pgroupVolumeMember,
+		"purestorage_protectiongroup_host":      pgroupHostMember,
+		"purestorage_protectiongroup_hostgroup": pgroupHostgroupMember,
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		member, ok := members[rs.Type]
+		if !ok {
+			continue
+		}
+
+		p, _ := client.Protectiongroups.GetProtectiongroup(rs.Primary.Attributes["protection_group"], nil)
+		if p != nil && stringInSlice(rs.Primary.Attributes[member.key], member.members(p)) {
+			return fmt.Errorf("member '%s' still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckPureProtectiongroupMemberExists(n string, key string, exists bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		client := testAccProvider.Meta().(*flasharray.Client)
+		p, err := client.Protectiongroups.GetProtectiongroup(rs.Primary.Attributes["protection_group"], nil)
+		if err != nil {
+			return fmt.Errorf("protectiongroup does not exist: %s", n)
+		}
+
+		members := map[string][]string{"volume": p.Volumes, "host": p.Hosts, "hostgroup": p.Hgroups}
+		if !stringInSlice(rs.Primary.Attributes[key], members[key]) {
+			if exists {
+				return fmt.Errorf("member does not exist: %s", n)
+			}
+		}
+		return nil
+	}
+}
+
+func testAccCheckPureProtectiongroupVolumeConfig(rInt int) string {
+	return fmt.Sprintf(`
+resource "purestorage_protectiongroup" "tfpgroupmembertest" {
+	name = "tfpgroupmembertest-%d"
+
+	lifecycle {
+		ignore_changes = ["volumes"]
+	}
+}
+
+resource "purestorage_volume" "a" {
+	name = "tfpgroupmembertest-a-%d"
+	size = 1024000000
+}
+
+resource "purestorage_volume" "b" {
+	name = "tfpgroupmembertest-b-%d"
+	size = 1024000000
+}
+
+resource "purestorage_protectiongroup_volume" "a" {
+	protection_group = "${purestorage_protectiongroup.tfpgroupmembertest.name}"
+	volume           = "${purestorage_volume.a.name}"
+}
+
+resource "purestorage_protectiongroup_volume" "b" {
+	protection_group = "${purestorage_protectiongroup.tfpgroupmembertest.name}"
+	volume           = "${purestorage_volume.b.name}"
+}`, rInt, rInt, rInt)
+}
+
+func testAccCheckPureProtectiongroupHostConfig(rInt int) string {
+	return fmt.Sprintf(`
+resource "purestorage_protectiongroup" "tfpgroupmembertest" {
+	name = "tfpgroupmembertest-%d"
+
+	lifecycle {
+		ignore_changes = ["hosts"]
+	}
+}
+
+resource "purestorage_host" "a" {
+	name = "tfpgroupmembertest-a-%d"
+}
+
+resource "purestorage_protectiongroup_host" "a" {
+	protection_group = "${purestorage_protectiongroup.tfpgroupmembertest.name}"
+	host             = "${purestorage_host.a.name}"
+}`, rInt, rInt)
+}
+
+func testAccCheckPureProtectiongroupHostgroupConfig(rInt int) string {
+	return fmt.Sprintf(`
+resource "purestorage_protectiongroup" "tfpgroupmembertest" {
+	name = "tfpgroupmembertest-%d"
+
+	lifecycle {
+		ignore_changes = ["hgroups"]
+	}
+}
+
+resource "purestorage_hostgroup" "a" {
+	name = "tfpgroupmembertest-a-%d"
+}
+
+resource "purestorage_protectiongroup_hostgroup" "a" {
+	protection_group = "${purestorage_protectiongroup.tfpgroupmembertest.name}"
+	hostgroup        = "${purestorage_hostgroup.a.name}"
+}`, rInt, rInt)
+}
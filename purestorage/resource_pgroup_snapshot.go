@@ -0,0 +1,237 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"fmt"
+
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourcePureProtectiongroupSnapshot() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePureProtectiongroupSnapshotCreate,
+		Read:   resourcePureProtectiongroupSnapshotRead,
+		Update: resourcePureProtectiongroupSnapshotUpdate,
+		Delete: resourcePureProtectiongroupSnapshotDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourcePureProtectiongroupSnapshotImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"protection_group": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Name of the protection group to snapshot.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"suffix": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Suffix of the snapshot. If not provided, the array will generate one.",
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+			},
+			"replicate_now": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: "Replicate the snapshot to the protection group targets as soon as it is taken.",
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+			},
+			"apply_retention": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: "Apply the protection group retention policy to the snapshot, so it is eradicated like a scheduled snapshot.",
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+			},
+			"destroy_mode": schemaDestroyMode(),
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"created": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"volume_snapshots": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: "The volume snapshots in the protection group snapshot.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"source": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"created": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// resourcePureProtectiongroupSnapshotCreate takes a snapshot of the given
+// protection group.  CreatePgroupSnapshot does not take a suffix or any of
+// the other options, so the snapshot is requested directly.
+func resourcePureProtectiongroupSnapshotCreate(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	data := map[string]interface{}{
+		"snap":   true,
+		"source": []string{d.Get("protection_group").(string)},
+	}
+	if suffix, ok := d.GetOk("suffix"); ok {
+		data["suffix"] = suffix.(string)
+	}
+	if d.Get("replicate_now").(bool) {
+		data["replicate_now"] = true
+	}
+	if d.Get("apply_retention").(bool) {
+		data["apply_retention"] = true
+	}
+
+	req, err := client.NewRequest("POST", "pgroup", nil, data)
+	if err != nil {
+		return err
+	}
+	snapshots := []flasharray.ProtectiongroupSnapshot{}
+	if _, err = client.Do(req, &snapshots, false); err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		return fmt.Errorf("no snapshot was returned for protection group %s", d.Get("protection_group").(string))
+	}
+
+	d.SetId(snapshots[0].Name)
+	return resourcePureProtectiongroupSnapshotRead(d, m)
+}
+
+func resourcePureProtectiongroupSnapshotRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	snapshot, _ := getPgroupSnapshot(client, d.Id())
+
+	if snapshot == nil {
+		d.SetId("")
+		return nil
+	}
+
+	return setPgroupSnapshot(d, client, snapshot)
+}
+
+// resourcePureProtectiongroupSnapshotUpdate only needs to store the new
+// value of destroy_mode, every other argument forces a new snapshot.
+func resourcePureProtectiongroupSnapshotUpdate(d *schema.ResourceData, m interface{}) error {
+	return resourcePureProtectiongroupSnapshotRead(d, m)
+}
+
+// resourcePureProtectiongroupSnapshotDelete destroys the snapshot, which
+// destroys all of its volume snapshots.  The snapshot is only eradicated if
+// destroy_mode is set to eradicate.
+func resourcePureProtectiongroupSnapshotDelete(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	if _, err := client.Protectiongroups.DestroyProtectiongroup(d.Id()); err != nil {
+		return err
+	}
+
+	if d.Get("destroy_mode").(string) == destroyModeEradicate {
+		if _, err := client.Protectiongroups.EradicateProtectiongroup(d.Id()); err != nil {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourcePureProtectiongroupSnapshotImport imports a protection group
+// snapshot into Terraform.  The ID must be in the form pgroup.suffix.
+func resourcePureProtectiongroupSnapshotImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*flasharray.Client)
+
+	if _, _, err := splitSnapshotName(d.Id()); err != nil {
+		return nil, err
+	}
+
+	snapshot, err := getPgroupSnapshot(client, d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	if snapshot == nil {
+		return nil, fmt.Errorf("protection group snapshot %s does not exist", d.Id())
+	}
+
+	d.Set("replicate_now", false)
+	d.Set("apply_retention", false)
+	d.Set("destroy_mode", destroyModeDestroy)
+	if err := setPgroupSnapshot(d, client, snapshot); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// setPgroupSnapshot sets the attributes of a protection group snapshot and
+// its volume snapshots.
+func setPgroupSnapshot(d *schema.ResourceData, client *flasharray.Client, snapshot *flasharray.ProtectiongroupSnapshot) error {
+	volumes, err := client.Volumes.ListVolumes(map[string]string{"snap": "true", "pgrouplist": snapshot.Name})
+	if err != nil {
+		return err
+	}
+
+	pgroup, suffix, _ := splitSnapshotName(snapshot.Name)
+
+	d.Set("protection_group", pgroup)
+	d.Set("suffix", suffix)
+	d.Set("name", snapshot.Name)
+	d.Set("created", snapshot.Created)
+	if err := d.Set("volume_snapshots", flattenPgroupVolumeSnapshots(volumes)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// getPgroupSnapshot returns the named protection group snapshot, or nil if
+// it does not exist.
+func getPgroupSnapshot(client *flasharray.Client, name string) (*flasharray.ProtectiongroupSnapshot, error) {
+	req, err := client.NewRequest("GET", "pgroup", map[string]string{"snap": "true", "names": name}, nil)
+	if err != nil {
+		return nil, err
+	}
+	snapshots := []flasharray.ProtectiongroupSnapshot{}
+	if _, err = client.Do(req, &snapshots, false); err != nil {
+		return nil, err
+	}
+
+	for _, s := range snapshots {
+		if s.Name == name {
+			return &s, nil
+		}
+	}
+	return nil, nil
+}
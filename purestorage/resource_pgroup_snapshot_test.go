@@ -0,0 +1,120 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+const testAccCheckPureProtectiongroupSnapshotResourceName = "purestorage_protectiongroup_snapshot.tfpgsnaptest"
+
+// Create a protection group snapshot
+func TestAccResourcePureProtectiongroupSnapshot_create(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureProtectiongroupSnapshotDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureProtectiongroupSnapshotConfig(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureProtectiongroupSnapshotExists(testAccCheckPureProtectiongroupSnapshotResourceName, true),
+					resource.TestCheckResourceAttr(testAccCheckPureProtectiongroupSnapshotResourceName, "name", fmt.Sprintf("tfpgsnaptest-%d.tfsnap", rInt)),
+					resource.TestCheckResourceAttr(testAccCheckPureProtectiongroupSnapshotResourceName, "volume_snapshots.#", "1"),
+					resource.TestCheckResourceAttr(testAccCheckPureProtectiongroupSnapshotResourceName, "volume_snapshots.0.name", fmt.Sprintf("tfpgsnaptest-%d.tfsnap.tfpgsnaptest-%d", rInt, rInt)),
+					resource.TestCheckResourceAttr(testAccCheckPureProtectiongroupSnapshotResourceName, "volume_snapshots.0.source", fmt.Sprintf("tfpgsnaptest-%d", rInt)),
+					resource.TestCheckResourceAttrSet(testAccCheckPureProtectiongroupSnapshotResourceName, "created"),
+				),
+			},
+			{
+				ResourceName:            testAccCheckPureProtectiongroupSnapshotResourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"destroy_mode", "apply_retention"},
+			},
+		},
+	})
+}
+
+func testAccCheckPureProtectiongroupSnapshotDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*flasharray.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "purestorage_protectiongroup_snapshot" {
+			continue
+		}
+
+		snapshot, _ := getPgroupSnapshot(client, rs.Primary.ID)
+		if snapshot == nil {
+			return nil
+		}
+		return fmt.Errorf("protection group snapshot '%s' stil exists", rs.Primary.ID)
+	}
+
+	return nil
+}
+
+func testAccCheckPureProtectiongroupSnapshotExists(n string, exists bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		client := testAccProvider.Meta().(*flasharray.Client)
+		snapshot, err := getPgroupSnapshot(client, rs.Primary.ID)
+		if err != nil || snapshot == nil {
+			if exists {
+				return fmt.Errorf("protection group snapshot does not exist: %s", n)
+			}
+			return nil
+		}
+		return nil
+	}
+}
+
+func testAccCheckPureProtectiongroupSnapshotConfig(rInt int) string {
+	return fmt.Sprintf(`
+resource "purestorage_volume" "tfpgsnaptest-volume" {
+	name = "tfpgsnaptest-%d"
+	size = 1024000000
+}
+
+resource "purestorage_protectiongroup" "tfpgsnaptest-pgroup" {
+	name    = "tfpgsnaptest-%d"
+	volumes = ["${purestorage_volume.tfpgsnaptest-volume.name}"]
+}
+
+resource "purestorage_protectiongroup_snapshot" "tfpgsnaptest" {
+	protection_group = "${purestorage_protectiongroup.tfpgsnaptest-pgroup.name}"
+	suffix           = "tfsnap"
+	apply_retention  = true
+	destroy_mode     = "eradicate"
+}`, rInt, rInt)
+}
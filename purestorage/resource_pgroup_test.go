@@ -19,10 +19,13 @@ package purestorage
 import (
 	"fmt"
 	"math/rand"
+	"os"
+	"regexp"
 	"testing"
 
 	"github.com/devans10/pugo/flasharray"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 )
 
@@ -119,6 +122,42 @@ func TestAccResourcePureProtectiongroup_create_withSchedule(t *testing.T) {
 				Config: testAccCheckPureProtectiongroupConfigWithSchedule(rInt),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckPureProtectiongroupExists(testAccCheckPureProtectiongroupResourceName, true),
+					resource.TestCheckResourceAttr(testAccCheckPureProtectiongroupResourceName, "replicate_at", "3600"),
+					resource.TestCheckResourceAttr(testAccCheckPureProtectiongroupResourceName, "replicate_frequency", "86400"),
+					resource.TestCheckResourceAttr(testAccCheckPureProtectiongroupResourceName, "snap_at", "7200"),
+					resource.TestCheckResourceAttr(testAccCheckPureProtectiongroupResourceName, "snap_frequency", "43200"),
+					resource.TestCheckResourceAttr(testAccCheckPureProtectiongroupResourceName, "blackout.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+// Replicate to the array named by PURE_REPLICATION_TARGET, which must already
+// be connected to the array under test.
+func TestAccResourcePureProtectiongroup_create_withTarget(t *testing.T) {
+	target := os.Getenv("PURE_REPLICATION_TARGET")
+	if target == "" {
+		t.Skip("set PURE_REPLICATION_TARGET to test replication targets")
+	}
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureProtectiongroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureProtectiongroupConfigWithTarget(rInt, target),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureProtectiongroupExists(testAccCheckPureProtectiongroupResourceName, true),
+					resource.TestCheckResourceAttr(testAccCheckPureProtectiongroupResourceName, "target.#", "1"),
+				),
+			},
+			{
+				Config: testAccCheckPureProtectiongroupConfigBasic(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccCheckPureProtectiongroupResourceName, "target.#", "0"),
 				),
 			},
 		},
@@ -239,8 +278,18 @@ func TestAccResourcePureProtectiongroup_update_withSchedule(t *testing.T) {
 				Config: testAccCheckPureProtectiongroupConfigWithSchedule(rInt),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckPureProtectiongroupExists(testAccCheckPureProtectiongroupResourceName, true),
+					resource.TestCheckResourceAttr(testAccCheckPureProtectiongroupResourceName, "replicate_at", "3600"),
+					resource.TestCheckResourceAttr(testAccCheckPureProtectiongroupResourceName, "replicate_frequency", "86400"),
+					resource.TestCheckResourceAttr(testAccCheckPureProtectiongroupResourceName, "snap_at", "7200"),
+					resource.TestCheckResourceAttr(testAccCheckPureProtectiongroupResourceName, "snap_frequency", "43200"),
+					resource.TestCheckResourceAttr(testAccCheckPureProtectiongroupResourceName, "blackout.#", "1"),
 				),
 			},
+			{
+				// Overlapping blackouts are rejected before anything is changed.
+				Config:      testAccCheckPureProtectiongroupConfigWithOverlappingBlackouts(rInt),
+				ExpectError: regexp.MustCompile("overlaps another blackout"),
+			},
 		},
 	})
 }
@@ -269,6 +318,42 @@ func TestAccResourcePureProtectiongroup_update_withRetention(t *testing.T) {
 	})
 }
 
+func TestAccResourcePureProtectiongroup_eradicate(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureProtectiongroupEradicated,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureProtectiongroupConfigEradicate(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureProtectiongroupExists(testAccCheckPureProtectiongroupResourceName, true),
+					resource.TestCheckResourceAttr(testAccCheckPureProtectiongroupResourceName, "destroy_mode", "eradicate"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckPureProtectiongroupEradicated(s *terraform.State) error {
+	client := testAccProvider.Meta().(*flasharray.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "purestorage_protectiongroup" {
+			continue
+		}
+
+		pgroups, _ := client.Protectiongroups.ListProtectiongroups(map[string]string{"pending_only": "true", "names": rs.Primary.ID})
+		if len(pgroups) > 0 {
+			return fmt.Errorf("protection group '%s' was not eradicated", rs.Primary.ID)
+		}
+	}
+
+	return testAccCheckPureProtectiongroupDestroy(s)
+}
+
 func testAccCheckPureProtectiongroupDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(*flasharray.Client)
 
@@ -450,11 +535,35 @@ func testAccCheckPureProtectiongroupConfigWithSchedule(rInt int) string {
 resource "purestorage_protectiongroup" "tfprotectiongrouptest" {
         name = "tfprotectiongrouptest-%d"
 	replicate_enabled = "true"
-	replicate_at = "3600"
-	replicate_frequency = "86400"
+	replicate_at = "01:00"
+	replicate_frequency = "1d"
 	snap_enabled = "true"
-	snap_at = "60"
-	snap_frequency = "86400"
+	snap_at = "02:00"
+	snap_frequency = "12h"
+
+	blackout {
+		start = "08:00"
+		end   = "18:00"
+	}
+}`, rInt)
+}
+
+func testAccCheckPureProtectiongroupConfigWithOverlappingBlackouts(rInt int) string {
+	return fmt.Sprintf(`
+resource "purestorage_protectiongroup" "tfprotectiongrouptest" {
+	name              = "tfprotectiongrouptest-%d"
+	replicate_enabled = "true"
+	snap_enabled      = "false"
+
+	blackout {
+		start = "08:00"
+		end   = "18:00"
+	}
+
+	blackout {
+		start = "17:00"
+		end   = "20:00"
+	}
 }`, rInt)
 }
 
@@ -462,8 +571,80 @@ func testAccCheckPureProtectiongroupConfigWithRetention(rInt int) string {
 	return fmt.Sprintf(`
 resource "purestorage_protectiongroup" "tfprotectiongrouptest" {
 	name = "tfprotectiongrouptest-%d"
-	all_for = 86400
+	all_for = "1d"
 	days = 8
 	per_day = 5
 }`, rInt)
 }
+
+func testAccCheckPureProtectiongroupConfigWithTarget(rInt int, target string) string {
+	return fmt.Sprintf(`
+resource "purestorage_protectiongroup" "tfprotectiongrouptest" {
+	name = "tfprotectiongrouptest-%d"
+
+	target {
+		name = "%s"
+	}
+}`, rInt, target)
+}
+
+func testAccCheckPureProtectiongroupConfigEradicate(rInt int) string {
+	return fmt.Sprintf(`
+resource "purestorage_protectiongroup" "tfprotectiongrouptest" {
+	name         = "tfprotectiongrouptest-%d"
+	destroy_mode = "eradicate"
+}`, rInt)
+}
+
+func Test_pgroupScheduleBlackouts(t *testing.T) {
+	schedules := map[string]int{
+		`null`:                           0,
+		`{"start": 0, "end": 0}`:         0,
+		`{"start": 28800, "end": 64800}`: 1,
+		`[{"start": 0, "end": 3600}, {"start": 7200, "end": 10800}]`: 2,
+	}
+	for in, expected := range schedules {
+		s := &pgroupSchedule{ReplicateBlackout: []byte(in)}
+		blackouts, err := s.blackouts()
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if len(blackouts) != expected {
+			t.Fatalf("Wrong number of blackouts returned for %s: %d", in, len(blackouts))
+		}
+	}
+
+	s := &pgroupSchedule{ReplicateBlackout: []byte(`{"start": 28800, "end": 64800}`)}
+	blackouts, _ := s.blackouts()
+	if blackouts[0]["start"] != 28800 || blackouts[0]["end"] != 64800 {
+		t.Fatalf("Wrong blackout returned: %v", blackouts[0])
+	}
+}
+
+func Test_expandPgroupBlackouts(t *testing.T) {
+	newSet := func(windows ...[2]string) *schema.Set {
+		set := schema.NewSet(resourcePgroupBlackoutHash, nil)
+		for _, w := range windows {
+			set.Add(map[string]interface{}{"start": w[0], "end": w[1]})
+		}
+		return set
+	}
+
+	blackouts, err := expandPgroupBlackouts(newSet([2]string{"22:00", "02:00"}, [2]string{"08:00", "18:00"}))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(blackouts) != 2 {
+		t.Fatalf("Wrong number of blackouts returned: %d", len(blackouts))
+	}
+
+	for _, set := range []*schema.Set{
+		newSet([2]string{"08:00", "08:00"}),
+		newSet([2]string{"08:00", "18:00"}, [2]string{"17:00", "20:00"}),
+		newSet([2]string{"22:00", "02:00"}, [2]string{"01:00", "03:00"}),
+	} {
+		if _, err := expandPgroupBlackouts(set); err == nil {
+			t.Fatalf("Expected error for %v", set.List())
+		}
+	}
+}
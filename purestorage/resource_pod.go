@@ -0,0 +1,289 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// podArrays is the array membership of a pod.  The client's Pod type does
+// not include the arrays the pod is stretched to.
+type podArrays struct {
+	Name   string `json:"name,omitempty"`
+	Arrays []struct {
+		Name   string `json:"name,omitempty"`
+		Status string `json:"status,omitempty"`
+	} `json:"arrays,omitempty"`
+}
+
+func resourcePurePod() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePurePodCreate,
+		Read:   resourcePurePodRead,
+		Update: resourcePurePodUpdate,
+		Delete: resourcePurePodDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourcePurePodImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"arrays": &schema.Schema{
+				Type:        schema.TypeSet,
+				Description: "Arrays the pod is stretched to, in addition to the local array.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set:      schema.HashString,
+				Optional: true,
+			},
+			"failover_preference": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: "Arrays that should keep the pod online if the arrays lose contact with each other.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional: true,
+			},
+			"source": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"destroy_mode":         schemaDestroyMode(),
+			"recover_if_destroyed": schemaRecoverIfDestroyed(),
+		},
+	}
+}
+
+// resourcePurePodCreate creates a pod and stretches it to the given arrays.
+// If recover_if_destroyed is set and a pod with the same name is pending
+// eradication, that pod is recovered instead.
+func resourcePurePodCreate(d *schema.ResourceData, m interface{}) error {
+	d.Partial(true)
+
+	client := m.(*flasharray.Client)
+	var pod *flasharray.Pod
+	var err error
+
+	name := d.Get("name").(string)
+
+	if d.Get("recover_if_destroyed").(bool) {
+		if pod, err = recoverDestroyedPod(client, name); err != nil {
+			return err
+		}
+		if pod != nil {
+			log.Printf("[INFO] Recovered destroyed pod %s.", pod.Name)
+		}
+	}
+
+	if pod == nil {
+		if pod, err = client.Pods.CreatePod(name, nil); err != nil {
+			return err
+		}
+	}
+	d.SetId(pod.Name)
+	d.SetPartial("name")
+
+	if fp, ok := d.GetOk("failover_preference"); ok {
+		var failoverPreference []string
+		for _, element := range fp.([]interface{}) {
+			failoverPreference = append(failoverPreference, element.(string))
+		}
+		if _, err = client.Pods.SetPod(d.Id(), map[string]interface{}{"failover_preference": failoverPreference}); err != nil {
+			return err
+		}
+	}
+	d.SetPartial("failover_preference")
+
+	for _, array := range d.Get("arrays").(*schema.Set).List() {
+		if _, err = client.Pods.ConnectPod(d.Id(), array.(string)); err != nil {
+			return err
+		}
+	}
+	d.Partial(false)
+
+	return resourcePurePodRead(d, m)
+}
+
+func resourcePurePodRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	pod, _ := client.Pods.GetPod(d.Id(), nil)
+
+	if pod == nil {
+		d.SetId("")
+		return nil
+	}
+
+	arrays, err := getPodArrays(client, pod.Name)
+	if err != nil {
+		return err
+	}
+
+	d.Set("name", pod.Name)
+	d.Set("source", pod.Source)
+	d.Set("failover_preference", pod.FailoverPreference)
+	if err := d.Set("arrays", arrays); err != nil {
+		return err
+	}
+	return nil
+}
+
+// resourcePurePodUpdate renames the pod, stretches it to any new arrays and
+// unstretches it from any arrays that were removed.
+func resourcePurePodUpdate(d *schema.ResourceData, m interface{}) error {
+	d.Partial(true)
+
+	client := m.(*flasharray.Client)
+	var pod *flasharray.Pod
+	var err error
+
+	if d.HasChange("name") {
+		if pod, err = client.Pods.RenamePod(d.Id(), d.Get("name").(string)); err != nil {
+			return err
+		}
+		d.SetId(pod.Name)
+	}
+	d.SetPartial("name")
+
+	if d.HasChange("arrays") {
+		o, n := d.GetChange("arrays")
+		os := o.(*schema.Set)
+		ns := n.(*schema.Set)
+
+		for _, array := range ns.Difference(os).List() {
+			if _, err = client.Pods.ConnectPod(d.Id(), array.(string)); err != nil {
+				return err
+			}
+		}
+
+		for _, array := range os.Difference(ns).List() {
+			if _, err = client.Pods.DisconnectPod(d.Id(), array.(string)); err != nil {
+				return err
+			}
+		}
+	}
+	d.SetPartial("arrays")
+
+	if d.HasChange("failover_preference") {
+		failoverPreference := []string{}
+		for _, element := range d.Get("failover_preference").([]interface{}) {
+			failoverPreference = append(failoverPreference, element.(string))
+		}
+		if _, err = client.Pods.SetPod(d.Id(), map[string]interface{}{"failover_preference": failoverPreference}); err != nil {
+			return err
+		}
+	}
+	d.Partial(false)
+
+	return resourcePurePodRead(d, m)
+}
+
+// resourcePurePodDelete unstretches the pod and destroys it.  The pod is
+// only eradicated if destroy_mode is set to eradicate.
+func resourcePurePodDelete(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	for _, array := range d.Get("arrays").(*schema.Set).List() {
+		if _, err := client.Pods.DisconnectPod(d.Id(), array.(string)); err != nil {
+			return err
+		}
+	}
+
+	if _, err := client.Pods.DeletePod(d.Id()); err != nil {
+		return err
+	}
+
+	if d.Get("destroy_mode").(string) == destroyModeEradicate {
+		if _, err := client.Pods.EradicatePod(d.Id()); err != nil {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourcePurePodImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*flasharray.Client)
+
+	pod, err := client.Pods.GetPod(d.Id(), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	arrays, err := getPodArrays(client, pod.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("name", pod.Name)
+	d.Set("source", pod.Source)
+	d.Set("failover_preference", pod.FailoverPreference)
+	d.Set("arrays", arrays)
+	d.Set("destroy_mode", destroyModeDestroy)
+	d.Set("recover_if_destroyed", false)
+	return []*schema.ResourceData{d}, nil
+}
+
+// getPodArrays returns the arrays the pod is stretched to, not including
+// the local array.
+func getPodArrays(client *flasharray.Client, name string) ([]string, error) {
+	local, err := client.Array.Get(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := client.NewRequest("GET", fmt.Sprintf("pod/%s", name), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	p := &podArrays{}
+	if _, err = client.Do(req, p, false); err != nil {
+		return nil, err
+	}
+
+	arrays := []string{}
+	for _, array := range p.Arrays {
+		if array.Name != local.ArrayName {
+			arrays = append(arrays, array.Name)
+		}
+	}
+	return arrays, nil
+}
+
+// recoverDestroyedPod recovers the named pod if it is pending eradication,
+// see isPendingEradication.  nil is returned if there is no such pod.
+func recoverDestroyedPod(client *flasharray.Client, name string) (*flasharray.Pod, error) {
+	pending, err := isPendingEradication(client, "pod", name)
+	if err != nil || !pending {
+		return nil, err
+	}
+
+	if _, err = client.Pods.RecoverPod(name); err != nil {
+		return nil, err
+	}
+	return client.Pods.GetPod(name, nil)
+}
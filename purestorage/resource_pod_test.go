@@ -0,0 +1,147 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+const testAccCheckPurePodResourceName = "purestorage_pod.tfpodtest"
+
+// Create a pod
+func TestAccResourcePurePod_create(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPurePodDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPurePodConfig(rInt, "tfpodtest"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPurePodExists(testAccCheckPurePodResourceName, true),
+					resource.TestCheckResourceAttr(testAccCheckPurePodResourceName, "name", fmt.Sprintf("tfpodtest-%d", rInt)),
+					resource.TestCheckResourceAttr(testAccCheckPurePodResourceName, "arrays.#", "0"),
+				),
+			},
+			{
+				ResourceName:            testAccCheckPurePodResourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"destroy_mode"},
+			},
+			{
+				Config: testAccCheckPurePodConfig(rInt, "tfpodtestrename"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccCheckPurePodResourceName, "name", fmt.Sprintf("tfpodtestrename-%d", rInt)),
+				),
+			},
+		},
+	})
+}
+
+// Create a volume in a pod
+func TestAccResourcePurePod_withVolume(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPurePodDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPurePodConfigWithVolume(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPurePodExists(testAccCheckPurePodResourceName, true),
+					resource.TestCheckResourceAttr("purestorage_volume.tfpodtest-volume", "id", fmt.Sprintf("tfpodtest-%d::tfpodtest-volume", rInt)),
+					resource.TestCheckResourceAttr("purestorage_volume.tfpodtest-volume", "pod", fmt.Sprintf("tfpodtest-%d", rInt)),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckPurePodDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*flasharray.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "purestorage_pod" {
+			continue
+		}
+
+		_, err := client.Pods.GetPod(rs.Primary.ID, nil)
+		if err != nil {
+			return nil
+		}
+		return fmt.Errorf("pod '%s' stil exists", rs.Primary.ID)
+	}
+
+	return nil
+}
+
+func testAccCheckPurePodExists(n string, exists bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		client := testAccProvider.Meta().(*flasharray.Client)
+		_, err := client.Pods.GetPod(rs.Primary.ID, nil)
+		if err != nil {
+			if exists {
+				return fmt.Errorf("pod does not exist: %s", n)
+			}
+			return nil
+		}
+		return nil
+	}
+}
+
+func testAccCheckPurePodConfig(rInt int, prefix string) string {
+	return fmt.Sprintf(`
+resource "purestorage_pod" "tfpodtest" {
+	name         = "%s-%d"
+	destroy_mode = "eradicate"
+}`, prefix, rInt)
+}
+
+func testAccCheckPurePodConfigWithVolume(rInt int) string {
+	return fmt.Sprintf(`
+resource "purestorage_pod" "tfpodtest" {
+	name         = "tfpodtest-%d"
+	destroy_mode = "eradicate"
+}
+
+resource "purestorage_volume" "tfpodtest-volume" {
+	name         = "tfpodtest-volume"
+	size         = "1G"
+	pod          = "${purestorage_pod.tfpodtest.name}"
+	destroy_mode = "eradicate"
+}`, rInt)
+}
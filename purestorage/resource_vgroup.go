@@ -0,0 +1,160 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"log"
+
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourcePureVolumeGroup() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePureVolumeGroupCreate,
+		Read:   resourcePureVolumeGroupRead,
+		Update: resourcePureVolumeGroupUpdate,
+		Delete: resourcePureVolumeGroupDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourcePureVolumeGroupImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"volumes": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: "List of volumes in the volume group.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
+			"destroy_mode":         schemaDestroyMode(),
+			"recover_if_destroyed": schemaRecoverIfDestroyed(),
+		},
+	}
+}
+
+// resourcePureVolumeGroupCreate creates a volume group.  If recover_if_destroyed
+// is set and a volume group with the same name is pending eradication, that
+// volume group is recovered instead.
+func resourcePureVolumeGroupCreate(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	var vgroup *flasharray.Vgroup
+	var err error
+
+	name := d.Get("name").(string)
+	if d.Get("recover_if_destroyed").(bool) {
+		if vgroup, err = recoverDestroyedVgroup(client, name); err != nil {
+			return err
+		}
+		if vgroup != nil {
+			log.Printf("[INFO] Recovered destroyed volume group %s.", vgroup.Name)
+		}
+	}
+
+	if vgroup == nil {
+		if vgroup, err = client.Vgroups.CreateVgroup(name); err != nil {
+			return err
+		}
+	}
+
+	d.SetId(vgroup.Name)
+	return resourcePureVolumeGroupRead(d, m)
+}
+
+func resourcePureVolumeGroupRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	vgroup, _ := client.Vgroups.GetVgroup(d.Id())
+
+	if vgroup == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", vgroup.Name)
+	d.Set("volumes", vgroup.Volumes)
+	return nil
+}
+
+func resourcePureVolumeGroupUpdate(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	if d.HasChange("name") {
+		vgroup, err := client.Vgroups.RenameVgroup(d.Id(), d.Get("name").(string))
+		if err != nil {
+			return err
+		}
+		d.SetId(vgroup.Name)
+	}
+
+	return resourcePureVolumeGroupRead(d, m)
+}
+
+// resourcePureVolumeGroupDelete destroys the volume group.  The volume group
+// is only eradicated if destroy_mode is set to eradicate.
+func resourcePureVolumeGroupDelete(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	if _, err := client.Vgroups.DestroyVgroup(d.Id()); err != nil {
+		return err
+	}
+
+	if d.Get("destroy_mode").(string) == destroyModeEradicate {
+		if _, err := client.Vgroups.EradicateVgroup(d.Id()); err != nil {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourcePureVolumeGroupImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*flasharray.Client)
+
+	vgroup, err := client.Vgroups.GetVgroup(d.Id())
+
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("name", vgroup.Name)
+	d.Set("volumes", vgroup.Volumes)
+	d.Set("destroy_mode", destroyModeDestroy)
+	d.Set("recover_if_destroyed", false)
+	return []*schema.ResourceData{d}, nil
+}
+
+// recoverDestroyedVgroup recovers the named volume group if it is pending eradication,
+// see isPendingEradication.  nil is returned if there is no such volume group.
+func recoverDestroyedVgroup(client *flasharray.Client, name string) (*flasharray.Vgroup, error) {
+	pending, err := isPendingEradication(client, "vgroup", name)
+	if err != nil || !pending {
+		return nil, err
+	}
+
+	if _, err = client.Vgroups.RecoverVgroup(name); err != nil {
+		return nil, err
+	}
+	return client.Vgroups.GetVgroup(name)
+}
@@ -0,0 +1,160 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+const testAccCheckPureVolumeGroupResourceName = "purestorage_volume_group.tfvgrouptest"
+
+// Create a volume group
+func TestAccResourcePureVolumeGroup_create(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureVolumeGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureVolumeGroupConfig(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureVolumeGroupExists(testAccCheckPureVolumeGroupResourceName, true),
+					resource.TestCheckResourceAttr(testAccCheckPureVolumeGroupResourceName, "name", fmt.Sprintf("tfvgrouptest-%d", rInt)),
+				),
+			},
+			{
+				ResourceName:            testAccCheckPureVolumeGroupResourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"destroy_mode"},
+			},
+		},
+	})
+}
+
+// Add a volume to a volume group, rename the volume group, then remove
+// the volume from it.
+func TestAccResourcePureVolumeGroup_withVolume(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureVolumeGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureVolumeGroupConfigWithVolume(rInt, "tfvgrouptest", true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureVolumeGroupExists(testAccCheckPureVolumeGroupResourceName, true),
+					resource.TestCheckResourceAttr("purestorage_volume.tfvgrouptest-volume", "id", fmt.Sprintf("tfvgrouptest-%d/tfvgrouptest-volume", rInt)),
+					resource.TestCheckResourceAttr("purestorage_volume.tfvgrouptest-volume", "name", "tfvgrouptest-volume"),
+				),
+			},
+			{
+				Config: testAccCheckPureVolumeGroupConfigWithVolume(rInt, "tfvgrouptestrename", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccCheckPureVolumeGroupResourceName, "name", fmt.Sprintf("tfvgrouptestrename-%d", rInt)),
+					resource.TestCheckResourceAttr("purestorage_volume.tfvgrouptest-volume", "id", fmt.Sprintf("tfvgrouptestrename-%d/tfvgrouptest-volume", rInt)),
+				),
+			},
+			{
+				Config: testAccCheckPureVolumeGroupConfigWithVolume(rInt, "tfvgrouptestrename", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("purestorage_volume.tfvgrouptest-volume", "id", "tfvgrouptest-volume"),
+					resource.TestCheckResourceAttr("purestorage_volume.tfvgrouptest-volume", "volume_group", ""),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckPureVolumeGroupDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*flasharray.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "purestorage_volume_group" {
+			continue
+		}
+
+		vgroups, _ := client.Vgroups.ListVgroups()
+		for _, vgroup := range vgroups {
+			if vgroup.Name == rs.Primary.ID {
+				return fmt.Errorf("volume group '%s' stil exists", rs.Primary.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckPureVolumeGroupExists(n string, exists bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		client := testAccProvider.Meta().(*flasharray.Client)
+		_, err := client.Vgroups.GetVgroup(rs.Primary.ID)
+		if err != nil {
+			if exists {
+				return fmt.Errorf("volume group does not exist: %s", n)
+			}
+			return nil
+		}
+		return nil
+	}
+}
+
+func testAccCheckPureVolumeGroupConfig(rInt int) string {
+	return fmt.Sprintf(`
+resource "purestorage_volume_group" "tfvgrouptest" {
+	name         = "tfvgrouptest-%d"
+	destroy_mode = "eradicate"
+}`, rInt)
+}
+
+func testAccCheckPureVolumeGroupConfigWithVolume(rInt int, prefix string, member bool) string {
+	vgroup := ""
+	if member {
+		vgroup = "${purestorage_volume_group.tfvgrouptest.name}"
+	}
+	return fmt.Sprintf(`
+resource "purestorage_volume_group" "tfvgrouptest" {
+	name         = "%s-%d"
+	destroy_mode = "eradicate"
+}
+
+resource "purestorage_volume" "tfvgrouptest-volume" {
+	name         = "tfvgrouptest-volume"
+	size         = "1G"
+	volume_group = "%s"
+	destroy_mode = "eradicate"
+}`, prefix, rInt, vgroup)
+}
@@ -19,9 +19,12 @@ package purestorage
 import (
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
 
 	"github.com/devans10/pugo/flasharray"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 )
 
 func resourcePureVolume() *schema.Resource {
@@ -33,21 +36,70 @@ func resourcePureVolume() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: resourcePureVolumeImport,
 		},
+		CustomizeDiff: resourcePureVolumeCustomizeDiff,
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    resourcePureVolumeV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourcePureVolumeStateUpgradeV0,
+				Version: 0,
+			},
+		},
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
 			},
 			"size": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				Computed: true,
+				Type:         schema.TypeString,
+				Description:  "Size of the volume in bytes, or with a unit such as 500G or 2T.",
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateVolumeSize,
+				StateFunc:    normalizeVolumeSize,
 			},
 			"source": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: false,
-				Optional: true,
-				Computed: true,
+				Type:          schema.TypeString,
+				Required:      false,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"restore_from"},
+			},
+			"restore_from": &schema.Schema{
+				Type:          schema.TypeList,
+				Description:   "Protection group snapshot to copy the volume from.",
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"source"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"protection_group": &schema.Schema{
+							Type:        schema.TypeString,
+							Description: "Name of the protection group.",
+							Required:    true,
+						},
+						"source_array": &schema.Schema{
+							Type:        schema.TypeString,
+							Description: "Name of the array the protection group was replicated from. If not provided, the protection group is local.",
+							Optional:    true,
+						},
+						"snapshot": &schema.Schema{
+							Type:        schema.TypeString,
+							Description: "Suffix or name of the protection group snapshot, or latest for the newest snapshot.",
+							Required:    true,
+						},
+						"volume": &schema.Schema{
+							Type:        schema.TypeString,
+							Description: "Name of the volume in the protection group to copy. Only required if the snapshot has more than one volume.",
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"restored_snapshot": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Name of the volume snapshot the volume was last copied from by restore_from.",
+				Computed:    true,
 			},
 			"serial": &schema.Schema{
 				Type:     schema.TypeString,
@@ -59,15 +111,85 @@ func resourcePureVolume() *schema.Resource {
 				Optional: true,
 				Computed: true,
 			},
+			"allow_truncate": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: "Allow the volume to be shrunk. A snapshot of the volume is taken before it is truncated.",
+				Optional:    true,
+				Default:     false,
+			},
+			"truncate_snapshot": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Name of the snapshot taken before the volume was last truncated.",
+				Computed:    true,
+			},
+			"volume_group": &schema.Schema{
+				Type:          schema.TypeString,
+				Description:   "Name of the volume group to place the volume in.",
+				Optional:      true,
+				ConflictsWith: []string{"pod"},
+			},
+			"pod": &schema.Schema{
+				Type:          schema.TypeString,
+				Description:   "Name of the pod to place the volume in.",
+				Optional:      true,
+				ConflictsWith: []string{"volume_group"},
+			},
+			"bandwidth_limit": &schema.Schema{
+				Type:         schema.TypeString,
+				Description:  "Maximum bandwidth of the volume in bytes per second, or with a unit such as 100M.",
+				Optional:     true,
+				ValidateFunc: validateBandwidthLimit,
+				StateFunc:    normalizeVolumeSize,
+			},
+			"iops_limit": &schema.Schema{
+				Type:         schema.TypeInt,
+				Description:  "Maximum IOPS of the volume.",
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(100, 100000000),
+			},
+			"protection_groups": &schema.Schema{
+				Type:        schema.TypeSet,
+				Description: "Protection groups the volume is a member of. If not provided, the protection group membership of the volume is not managed.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set:      schema.HashString,
+				Optional: true,
+				Computed: true,
+			},
+			"destroy_mode":         schemaDestroyMode(),
+			"recover_if_destroyed": schemaRecoverIfDestroyed(),
 		},
 	}
 }
 
+// volumeQos is the QoS configuration of a volume.  The client's Volume type
+// does not include the QoS limits.
+type volumeQos struct {
+	Name           string `json:"name,omitempty"`
+	BandwidthLimit *int   `json:"bandwidth_limit,omitempty"`
+	IopsLimit      *int   `json:"iops_limit,omitempty"`
+}
+
+// Minimum REST API versions that support the QoS limits
+const (
+	bandwidthLimitRestVersion = "1.14"
+	iopsLimitRestVersion      = "1.17"
+)
+
 // resourcePureVolumeCreate creates a Pure Volume on a FlashArray according
 // to the schema Resource Data provided.
 // If the size parameter is provided, a new Volume of that size will be created.
 // If the source parameter is provided, a new Volume that is a copy of the source
 // volume will be created.
+// If restore_from is provided, it is resolved to a volume snapshot, which is
+// copied the same way.
+// If recover_if_destroyed is set and a volume with the same name is pending
+// eradication, that volume is recovered instead, and extended if it is smaller
+// than size.
+// If the volume_group or pod parameter is provided, the new volume is moved
+// into the volume group or pod.
+// The QoS limits and protection groups are applied once the volume exists.
 func resourcePureVolumeCreate(d *schema.ResourceData, m interface{}) error {
 	client := m.(*flasharray.Client)
 
@@ -76,37 +198,136 @@ func resourcePureVolumeCreate(d *schema.ResourceData, m interface{}) error {
 
 	n, _ := d.GetOk("name")
 	s, _ := d.GetOk("source")
-	if s.(string) == "" {
-		z, _ := d.GetOk("size")
-		if v, err = client.Volumes.CreateVolume(n.(string), z.(int)); err != nil {
+	pod := d.Get("pod").(string)
+	vg := d.Get("volume_group").(string)
+
+	if d.Get("recover_if_destroyed").(bool) {
+		if v, err = recoverDestroyedVolume(client, volumeFullName(n.(string), pod, vg)); err != nil {
 			return err
 		}
-	} else {
-		if v, err = client.Volumes.CopyVolume(n.(string), s.(string), false); err != nil {
+		if v != nil {
+			log.Printf("[INFO] Recovered destroyed volume %s.", v.Name)
+			z, _ := parseVolumeSize(d.Get("size").(string))
+			if z > v.Size {
+				if v, err = client.Volumes.ExtendVolume(v.Name, z); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if v == nil {
+		if restore, ok := d.GetOk("restore_from.0"); ok {
+			snapshot, err := resolveRestoreSnapshot(client, restore.(map[string]interface{}))
+			if err != nil {
+				return err
+			}
+			if v, err = client.Volumes.CopyVolume(n.(string), snapshot, false); err != nil {
+				return err
+			}
+			d.Set("restored_snapshot", snapshot)
+		} else if s.(string) == "" {
+			z, _ := parseVolumeSize(d.Get("size").(string))
+			if v, err = client.Volumes.CreateVolume(n.(string), z); err != nil {
+				return err
+			}
+		} else {
+			if v, err = client.Volumes.CopyVolume(n.(string), s.(string), false); err != nil {
+				return err
+			}
+		}
+	}
+
+	if container := pod + vg; container != "" && v.Name != volumeFullName(n.(string), pod, vg) {
+		if v, err = client.Volumes.MoveVolume(v.Name, container); err != nil {
 			return err
 		}
 	}
 
 	d.SetId(v.Name)
+
+	qos := make(map[string]interface{})
+	if _, ok := d.GetOk("bandwidth_limit"); ok {
+		qos["bandwidth_limit"] = volumeQosValue(d, "bandwidth_limit")
+	}
+	if _, ok := d.GetOk("iops_limit"); ok {
+		qos["iops_limit"] = volumeQosValue(d, "iops_limit")
+	}
+	if len(qos) > 0 {
+		if _, err = client.Volumes.SetVolume(d.Id(), qos); err != nil {
+			return err
+		}
+	}
+
+	if pgroups, ok := d.GetOk("protection_groups"); ok {
+		// A recovered volume may still be in some of the protection groups.
+		current, err := getVolumeProtectiongroups(client, d.Id())
+		if err != nil {
+			return err
+		}
+		existing := schema.NewSet(schema.HashString, nil)
+		for _, pgroup := range current {
+			existing.Add(pgroup)
+		}
+		for _, pgroup := range pgroups.(*schema.Set).Difference(existing).List() {
+			if _, err = client.Volumes.AddVolume(d.Id(), pgroup.(string)); err != nil {
+				return err
+			}
+		}
+	}
+
 	return resourcePureVolumeRead(d, m)
 }
 
-// resourcePureVolumeRead sets the values for the given volume ID
+// resourcePureVolumeRead sets the values for the given volume ID.
+// If the volume is not found by name, it is looked up by serial, since
+// renaming the volume group or pod it is in also renames the volume.
 func resourcePureVolumeRead(d *schema.ResourceData, m interface{}) error {
 	client := m.(*flasharray.Client)
 
 	vol, _ := client.Volumes.GetVolume(d.Id(), nil)
 
+	if vol == nil && d.Get("serial").(string) != "" {
+		vol, _ = getVolumeBySerial(client, d.Get("serial").(string))
+	}
+
 	if vol == nil {
 		d.SetId("")
 		return nil
 	}
 
-	d.Set("name", vol.Name)
-	d.Set("size", vol.Size)
+	d.SetId(vol.Name)
+	pod, vg, name := splitVolumeName(vol.Name)
+	d.Set("name", name)
+	d.Set("pod", pod)
+	d.Set("volume_group", vg)
+	d.Set("size", strconv.Itoa(vol.Size))
 	d.Set("serial", vol.Serial)
 	d.Set("created", vol.Created)
 	d.Set("source", vol.Source)
+
+	pgroups, err := getVolumeProtectiongroups(client, vol.Name)
+	if err != nil {
+		return err
+	}
+	d.Set("protection_groups", pgroups)
+
+	if restVersionAtLeast(client.RestVersion, bandwidthLimitRestVersion) {
+		qos, err := getVolumeQos(client, vol.Name)
+		if err != nil {
+			return err
+		}
+		d.Set("bandwidth_limit", "")
+		if qos.BandwidthLimit != nil {
+			d.Set("bandwidth_limit", strconv.Itoa(*qos.BandwidthLimit))
+		}
+		if restVersionAtLeast(client.RestVersion, iopsLimitRestVersion) {
+			d.Set("iops_limit", 0)
+			if qos.IopsLimit != nil {
+				d.Set("iops_limit", *qos.IopsLimit)
+			}
+		}
+	}
 	return nil
 }
 
@@ -116,9 +337,9 @@ func resourcePureVolumeRead(d *schema.ResourceData, m interface{}) error {
 // taken before the source volume is copied over the current volume. This
 // should help protect from any accidental overwrites.
 //
-// If a new size is provided, it must be larger than the current size.  Only
-// extending volumes is supported at this time, since truncating volumes can
-// lead to data loss.
+// If a new size is provided, it must be larger than the current size, unless
+// allow_truncate is set.  Since truncating volumes can lead to data loss, a
+// snapshot of the current volume will be taken before it is truncated.
 func resourcePureVolumeUpdate(d *schema.ResourceData, m interface{}) error {
 	d.Partial(true)
 
@@ -127,13 +348,58 @@ func resourcePureVolumeUpdate(d *schema.ResourceData, m interface{}) error {
 	var err error
 
 	if d.HasChange("name") {
-		if v, err = client.Volumes.RenameVolume(d.Id(), d.Get("name").(string)); err != nil {
+		pod, vg, _ := splitVolumeName(d.Id())
+		if v, err = client.Volumes.RenameVolume(d.Id(), volumeFullName(d.Get("name").(string), pod, vg)); err != nil {
 			return err
 		}
 		d.SetId(v.Name)
 	}
 	d.SetPartial("name")
 
+	if d.HasChange("volume_group") || d.HasChange("pod") {
+		container := d.Get("pod").(string) + d.Get("volume_group").(string)
+		if v, err = client.Volumes.MoveVolume(d.Id(), container); err != nil {
+			return err
+		}
+		d.SetId(v.Name)
+	}
+	d.SetPartial("volume_group")
+	d.SetPartial("pod")
+
+	qos := make(map[string]interface{})
+	if d.HasChange("bandwidth_limit") {
+		qos["bandwidth_limit"] = volumeQosValue(d, "bandwidth_limit")
+	}
+	if d.HasChange("iops_limit") {
+		qos["iops_limit"] = volumeQosValue(d, "iops_limit")
+	}
+	if len(qos) > 0 {
+		if _, err = client.Volumes.SetVolume(d.Id(), qos); err != nil {
+			return err
+		}
+	}
+	d.SetPartial("bandwidth_limit")
+	d.SetPartial("iops_limit")
+
+	if d.HasChange("protection_groups") {
+		o, n := d.GetChange("protection_groups")
+		os := o.(*schema.Set)
+		ns := n.(*schema.Set)
+
+		for _, pgroup := range ns.Difference(os).List() {
+			if _, err = client.Volumes.AddVolume(d.Id(), pgroup.(string)); err != nil {
+				return err
+			}
+		}
+
+		for _, pgroup := range os.Difference(ns).List() {
+			if _, err = client.Volumes.RemoveVolume(d.Id(), pgroup.(string)); err != nil {
+				return err
+			}
+		}
+	}
+	d.SetPartial("protection_groups")
+
 	if d.HasChange("source") {
 		snapshot, err := client.Volumes.CreateSnapshot(d.Id(), "")
 		if err != nil {
@@ -146,16 +412,51 @@ func resourcePureVolumeUpdate(d *schema.ResourceData, m interface{}) error {
 	}
 	d.SetPartial("source")
 
+	if d.HasChange("restore_from") {
+		if restore, ok := d.GetOk("restore_from.0"); ok {
+			snapshot, err := resolveRestoreSnapshot(client, restore.(map[string]interface{}))
+			if err != nil {
+				return err
+			}
+			backup, err := client.Volumes.CreateSnapshot(d.Id(), "")
+			if err != nil {
+				return err
+			}
+			log.Printf("[INFO] Created volume snapshot %s before overwriting volume %s.", backup.Name, d.Id())
+			if _, err = client.Volumes.CopyVolume(d.Id(), snapshot, true); err != nil {
+				return err
+			}
+			d.Set("restored_snapshot", snapshot)
+		}
+	}
+	d.SetPartial("restore_from")
+	d.SetPartial("restored_snapshot")
+
 	if d.HasChange("size") {
 		oldVol, err := client.Volumes.GetVolume(d.Id(), nil)
-		z, _ := d.GetOk("size")
-		if z.(int) > oldVol.Size {
-			if _, err = client.Volumes.ExtendVolume(d.Id(), z.(int)); err != nil {
+		if err != nil {
+			return err
+		}
+		z, _ := parseVolumeSize(d.Get("size").(string))
+		if z > oldVol.Size {
+			if _, err = client.Volumes.ExtendVolume(d.Id(), z); err != nil {
 				return err
 			}
 		}
-		if z.(int) < oldVol.Size {
-			return fmt.Errorf("error: New size must be larger than current size. Truncating volumes not supported")
+		if z < oldVol.Size {
+			if !d.Get("allow_truncate").(bool) {
+				return fmt.Errorf("error: New size must be larger than current size. Set allow_truncate to shrink the volume")
+			}
+			snapshot, err := client.Volumes.CreateSnapshot(d.Id(), "")
+			if err != nil {
+				return err
+			}
+			log.Printf("[INFO] Created volume snapshot %s before truncating volume %s.", snapshot.Name, d.Id())
+			d.Set("truncate_snapshot", snapshot.Name)
+			d.SetPartial("truncate_snapshot")
+			if _, err = client.Volumes.TruncateVolume(d.Id(), z); err != nil {
+				return err
+			}
 		}
 	}
 	d.Partial(false)
@@ -163,10 +464,51 @@ func resourcePureVolumeUpdate(d *schema.ResourceData, m interface{}) error {
 	return resourcePureVolumeRead(d, m)
 }
 
+// resourcePureVolumeCustomizeDiff rejects QoS limits that the REST API
+// version of the session does not support, and a smaller size unless
+// allow_truncate is set, at plan time.  A new restore_from is only resolved
+// when it is applied, so the snapshot it restores is not known yet.
+func resourcePureVolumeCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	if b, ok := d.GetOk("bandwidth_limit"); ok && b.(string) != "" && !restVersionAtLeast(client.RestVersion, bandwidthLimitRestVersion) {
+		return fmt.Errorf("bandwidth_limit requires REST API version %s or later, the negotiated version is %s", bandwidthLimitRestVersion, client.RestVersion)
+	}
+
+	if _, ok := d.GetOk("iops_limit"); ok && !restVersionAtLeast(client.RestVersion, iopsLimitRestVersion) {
+		return fmt.Errorf("iops_limit requires REST API version %s or later, the negotiated version is %s", iopsLimitRestVersion, client.RestVersion)
+	}
+
+	if d.Id() != "" && d.HasChange("restore_from") {
+		if _, ok := d.GetOk("restore_from.0"); ok {
+			if err := d.SetNewComputed("restored_snapshot"); err != nil {
+				return err
+			}
+		}
+	}
+
+	if d.Id() == "" || !d.HasChange("size") {
+		return nil
+	}
+
+	o, n := d.GetChange("size")
+	oldSize, _ := parseVolumeSize(o.(string))
+	newSize, _ := parseVolumeSize(n.(string))
+	if newSize == 0 || newSize >= oldSize {
+		return nil
+	}
+
+	if !d.Get("allow_truncate").(bool) {
+		return fmt.Errorf("size of volume %s cannot be reduced from %d to %d bytes unless allow_truncate is set", d.Id(), oldSize, newSize)
+	}
+	return d.SetNewComputed("truncate_snapshot")
+}
+
 // resourcePureVolumeDelete will delete the volume specified.
-// The volume will NOT be eradicated. This is to reduce the chance of
-// data loss.  The volume's timer will start for 24 hours, at that time
-// the volume will be eradicated.
+// By default the volume will NOT be eradicated. This is to reduce the chance
+// of data loss.  The volume's timer will start for 24 hours, at that time
+// the volume will be eradicated.  If destroy_mode is set to eradicate, the
+// volume is eradicated immediately so the name can be reused.
 func resourcePureVolumeDelete(d *schema.ResourceData, m interface{}) error {
 	client := m.(*flasharray.Client)
 	_, err := client.Volumes.DeleteVolume(d.Id())
@@ -175,6 +517,12 @@ func resourcePureVolumeDelete(d *schema.ResourceData, m interface{}) error {
 		return err
 	}
 
+	if d.Get("destroy_mode").(string) == destroyModeEradicate {
+		if _, err = client.Volumes.EradicateVolume(d.Id()); err != nil {
+			return err
+		}
+	}
+
 	d.SetId("")
 	return nil
 }
@@ -189,10 +537,197 @@ func resourcePureVolumeImport(d *schema.ResourceData, m interface{}) ([]*schema.
 		return nil, err
 	}
 
-	d.Set("name", vol.Name)
-	d.Set("size", vol.Size)
+	pod, vg, name := splitVolumeName(vol.Name)
+	d.Set("name", name)
+	d.Set("pod", pod)
+	d.Set("volume_group", vg)
+	d.Set("size", strconv.Itoa(vol.Size))
 	d.Set("serial", vol.Serial)
 	d.Set("created", vol.Created)
 	d.Set("source", vol.Source)
+	if pgroups, err := getVolumeProtectiongroups(client, vol.Name); err == nil {
+		d.Set("protection_groups", pgroups)
+	}
+	d.Set("allow_truncate", false)
+	d.Set("destroy_mode", destroyModeDestroy)
+	d.Set("recover_if_destroyed", false)
 	return []*schema.ResourceData{d}, nil
 }
+
+// recoverDestroyedVolume recovers the named volume if it is pending eradication,
+// see isPendingEradication.  nil is returned if there is no such volume.
+func recoverDestroyedVolume(client *flasharray.Client, name string) (*flasharray.Volume, error) {
+	pending, err := isPendingEradication(client, "volume", name)
+	if err != nil || !pending {
+		return nil, err
+	}
+
+	if _, err = client.Volumes.RecoverVolume(name); err != nil {
+		return nil, err
+	}
+	return client.Volumes.GetVolume(name, nil)
+}
+
+// getVolumeBySerial returns the volume with the given serial, or nil if it
+// does not exist.
+func getVolumeBySerial(client *flasharray.Client, serial string) (*flasharray.Volume, error) {
+	volumes, err := client.Volumes.ListVolumes(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range volumes {
+		if strings.EqualFold(v.Serial, serial) {
+			return &v, nil
+		}
+	}
+	return nil, nil
+}
+
+// volumeFullName returns the name of a volume on the array, including the
+// pod or volume group it is in.  A volume group in a pod is named pod::vgroup.
+func volumeFullName(name string, pod string, vgroup string) string {
+	if vgroup != "" {
+		return vgroup + "/" + name
+	}
+	if pod != "" {
+		return pod + "::" + name
+	}
+	return name
+}
+
+// splitVolumeName splits the name of a volume on the array into the pod it
+// is in, the volume group it is in and its name within them.  A volume in a
+// volume group that is in a pod is returned with only the volume group set.
+func splitVolumeName(fullName string) (string, string, string) {
+	if i := strings.LastIndex(fullName, "/"); i >= 0 {
+		return "", fullName[:i], fullName[i+1:]
+	}
+	if i := strings.LastIndex(fullName, "::"); i >= 0 {
+		return fullName[:i], "", fullName[i+2:]
+	}
+	return "", "", fullName
+}
+
+// getVolumeQos returns the QoS limits of the volume.
+func getVolumeQos(client *flasharray.Client, name string) (*volumeQos, error) {
+	req, err := client.NewRequest("GET", fmt.Sprintf("volume/%s", name), map[string]string{"qos": "true"}, nil)
+	if err != nil {
+		return nil, err
+	}
+	qos := &volumeQos{}
+	if _, err = client.Do(req, qos, false); err != nil {
+		return nil, err
+	}
+	return qos, nil
+}
+
+// getVolumeProtectiongroups returns the names of the protection groups the
+// volume is a member of.
+func getVolumeProtectiongroups(client *flasharray.Client, name string) ([]string, error) {
+	req, err := client.NewRequest("GET", fmt.Sprintf("volume/%s", name), map[string]string{"protect": "true"}, nil)
+	if err != nil {
+		return nil, err
+	}
+	var members []flasharray.VolumePgroup
+	if _, err = client.Do(req, &members, false); err != nil {
+		return nil, err
+	}
+
+	pgroups := []string{}
+	for _, member := range members {
+		pgroups = append(pgroups, member.Pgroup)
+	}
+	return pgroups, nil
+}
+
+// volumeQosValue returns the value to send to the array for a QoS limit.
+// An empty string clears the limit.
+func volumeQosValue(d *schema.ResourceData, key string) interface{} {
+	switch v := d.Get(key).(type) {
+	case string:
+		if b, err := parseVolumeSize(v); err == nil && b > 0 {
+			return b
+		}
+	case int:
+		if v > 0 {
+			return v
+		}
+	}
+	return ""
+}
+
+// validateBandwidthLimit checks that a bandwidth limit can be parsed and is
+// within the range supported by Purity, 1 MB/s to 512 GB/s.
+func validateBandwidthLimit(v interface{}, k string) ([]string, []error) {
+	limit, err := parseVolumeSize(v.(string))
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s: %s", k, err)}
+	}
+	if limit < sizeUnits["M"] || limit > 512*sizeUnits["G"] {
+		return nil, []error{fmt.Errorf("%s: bandwidth limit %q must be between 1M and 512G", k, v.(string))}
+	}
+	return nil, nil
+}
+
+// resolveRestoreSnapshot returns the name of the volume snapshot to copy for
+// a restore_from block.  The protection group snapshot is named by its
+// suffix, its full name or latest, and replicated protection groups are
+// named source_array:protection_group on this array.
+func resolveRestoreSnapshot(client *flasharray.Client, restore map[string]interface{}) (string, error) {
+	pgroup := restore["protection_group"].(string)
+	if source := restore["source_array"].(string); source != "" {
+		pgroup = fmt.Sprintf("%s:%s", source, pgroup)
+	}
+
+	name := restore["snapshot"].(string)
+	switch {
+	case name == "latest":
+		snapshots, err := getPgroupSnapshots(client, pgroup)
+		if err != nil {
+			return "", err
+		}
+		if name = latestPgroupSnapshot(snapshots); name == "" {
+			return "", fmt.Errorf("protection group %s has no completed snapshots", pgroup)
+		}
+	case !strings.HasPrefix(name, pgroup+"."):
+		name = fmt.Sprintf("%s.%s", pgroup, name)
+	}
+
+	snapshot, err := getPgroupSnapshot(client, name)
+	if err != nil {
+		return "", err
+	}
+	if snapshot == nil {
+		return "", fmt.Errorf("protection group snapshot %s does not exist", name)
+	}
+
+	volumes, err := client.Volumes.ListVolumes(map[string]string{"snap": "true", "pgrouplist": name})
+	if err != nil {
+		return "", err
+	}
+	var names []string
+	for _, v := range volumes {
+		names = append(names, v.Name)
+	}
+
+	return selectRestoreVolumeSnapshot(name, names, restore["volume"].(string))
+}
+
+// selectRestoreVolumeSnapshot returns the snapshot of the named volume in a
+// protection group snapshot.  If no volume is named, the protection group
+// snapshot must have only one volume.
+func selectRestoreVolumeSnapshot(pgroupSnapshot string, volumeSnapshots []string, volume string) (string, error) {
+	if volume == "" {
+		if len(volumeSnapshots) != 1 {
+			return "", fmt.Errorf("protection group snapshot %s has %d volumes, set volume in restore_from to choose one", pgroupSnapshot, len(volumeSnapshots))
+		}
+		return volumeSnapshots[0], nil
+	}
+
+	name := fmt.Sprintf("%s.%s", pgroupSnapshot, volume)
+	if !stringInSlice(name, volumeSnapshots) {
+		return "", fmt.Errorf("protection group snapshot %s does not include volume %s", pgroupSnapshot, volume)
+	}
+	return name, nil
+}
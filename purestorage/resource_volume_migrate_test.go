@@ -0,0 +1,36 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"testing"
+)
+
+func Test_resourcePureVolumeStateUpgradeV0(t *testing.T) {
+	rawState := map[string]interface{}{
+		"name": "vol",
+		"size": float64(1024000000),
+	}
+
+	state, err := resourcePureVolumeStateUpgradeV0(rawState, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if state["size"] != "1024000000" {
+		t.Fatalf("Wrong value returned: %v", state["size"])
+	}
+}
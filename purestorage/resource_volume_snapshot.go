@@ -0,0 +1,187 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourcePureVolumeSnapshot() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePureVolumeSnapshotCreate,
+		Read:   resourcePureVolumeSnapshotRead,
+		Update: resourcePureVolumeSnapshotUpdate,
+		Delete: resourcePureVolumeSnapshotDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourcePureVolumeSnapshotImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"volume": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Name of the volume to snapshot.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"suffix": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Suffix of the snapshot. If not provided, the array will generate one.",
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+			},
+			"destroy_mode": schemaDestroyMode(),
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"serial": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"created": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"size": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// resourcePureVolumeSnapshotCreate takes a snapshot of the given volume,
+// using the suffix if one is provided.
+func resourcePureVolumeSnapshotCreate(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	snapshot, err := client.Volumes.CreateSnapshot(d.Get("volume").(string), d.Get("suffix").(string))
+	if err != nil {
+		return err
+	}
+
+	d.SetId(snapshot.Name)
+	return resourcePureVolumeSnapshotRead(d, m)
+}
+
+// resourcePureVolumeSnapshotRead sets the values for the given snapshot ID.
+// Snapshots are looked up through the volume list, since the volume endpoint
+// does not return a single snapshot by name.
+func resourcePureVolumeSnapshotRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	snapshot, _ := getVolumeSnapshot(client, d.Id())
+
+	if snapshot == nil {
+		d.SetId("")
+		return nil
+	}
+
+	volume, suffix, _ := splitSnapshotName(snapshot.Name)
+
+	d.Set("volume", volume)
+	d.Set("suffix", suffix)
+	d.Set("name", snapshot.Name)
+	d.Set("serial", snapshot.Serial)
+	d.Set("created", snapshot.Created)
+	d.Set("size", snapshot.Size)
+	return nil
+}
+
+// resourcePureVolumeSnapshotUpdate only needs to store the new value of
+// destroy_mode, every other argument forces a new snapshot.
+func resourcePureVolumeSnapshotUpdate(d *schema.ResourceData, m interface{}) error {
+	return resourcePureVolumeSnapshotRead(d, m)
+}
+
+// resourcePureVolumeSnapshotDelete destroys the snapshot.  Like volumes,
+// the snapshot is only eradicated if destroy_mode is set to eradicate.
+func resourcePureVolumeSnapshotDelete(d *schema.ResourceData, m interface{}) error {
+	client := m.(*flasharray.Client)
+
+	if _, err := client.Volumes.DeleteVolume(d.Id()); err != nil {
+		return err
+	}
+
+	if d.Get("destroy_mode").(string) == destroyModeEradicate {
+		if _, err := client.Volumes.EradicateVolume(d.Id()); err != nil {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourcePureVolumeSnapshotImport imports a snapshot into Terraform.
+// The ID must be in the form vol.suffix.
+func resourcePureVolumeSnapshotImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*flasharray.Client)
+
+	if _, _, err := splitSnapshotName(d.Id()); err != nil {
+		return nil, err
+	}
+
+	snapshot, err := getVolumeSnapshot(client, d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	if snapshot == nil {
+		return nil, fmt.Errorf("snapshot %s does not exist", d.Id())
+	}
+
+	volume, suffix, _ := splitSnapshotName(snapshot.Name)
+
+	d.Set("volume", volume)
+	d.Set("suffix", suffix)
+	d.Set("destroy_mode", destroyModeDestroy)
+	d.Set("name", snapshot.Name)
+	d.Set("serial", snapshot.Serial)
+	d.Set("created", snapshot.Created)
+	d.Set("size", snapshot.Size)
+	return []*schema.ResourceData{d}, nil
+}
+
+// getVolumeSnapshot returns the named volume snapshot, or nil if it does
+// not exist.
+func getVolumeSnapshot(client *flasharray.Client, name string) (*flasharray.Volume, error) {
+	snapshots, err := client.Volumes.ListVolumes(map[string]string{"snap": "true", "names": name})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range snapshots {
+		if s.Name == name {
+			return &s, nil
+		}
+	}
+	return nil, nil
+}
+
+// splitSnapshotName splits a snapshot name into its volume and suffix.
+func splitSnapshotName(name string) (string, string, error) {
+	i := strings.LastIndex(name, ".")
+	if i <= 0 || i == len(name)-1 {
+		return "", "", fmt.Errorf("invalid snapshot name %q, expected vol.suffix", name)
+	}
+	return name[:i], name[i+1:], nil
+}
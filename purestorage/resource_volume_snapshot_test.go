@@ -0,0 +1,129 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/devans10/pugo/flasharray"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+const testAccCheckPureVolumeSnapshotResourceName = "purestorage_volume_snapshot.tfsnapshottest"
+
+// Create a volume snapshot
+func TestAccResourcePureVolumeSnapshot_create(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureVolumeSnapshotDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureVolumeSnapshotConfig(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureVolumeSnapshotExists(testAccCheckPureVolumeSnapshotResourceName, true),
+					resource.TestCheckResourceAttr(testAccCheckPureVolumeSnapshotResourceName, "name", fmt.Sprintf("tfsnapshottest-%d.tfsnap", rInt)),
+					resource.TestCheckResourceAttr(testAccCheckPureVolumeSnapshotResourceName, "size", "1024000000"),
+					resource.TestCheckResourceAttrSet(testAccCheckPureVolumeSnapshotResourceName, "serial"),
+					resource.TestCheckResourceAttrSet(testAccCheckPureVolumeSnapshotResourceName, "created"),
+				),
+			},
+			{
+				ResourceName:            testAccCheckPureVolumeSnapshotResourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"destroy_mode"},
+			},
+		},
+	})
+}
+
+func testAccCheckPureVolumeSnapshotDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*flasharray.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "purestorage_volume_snapshot" {
+			continue
+		}
+
+		snapshot, _ := getVolumeSnapshot(client, rs.Primary.ID)
+		if snapshot == nil {
+			return nil
+		}
+		return fmt.Errorf("snapshot '%s' stil exists", rs.Primary.ID)
+	}
+
+	return nil
+}
+
+func testAccCheckPureVolumeSnapshotExists(n string, exists bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		client := testAccProvider.Meta().(*flasharray.Client)
+		snapshot, err := getVolumeSnapshot(client, rs.Primary.ID)
+		if err != nil || snapshot == nil {
+			if exists {
+				return fmt.Errorf("snapshot does not exist: %s", n)
+			}
+			return nil
+		}
+		return nil
+	}
+}
+
+func testAccCheckPureVolumeSnapshotConfig(rInt int) string {
+	return fmt.Sprintf(`
+resource "purestorage_volume" "tfsnapshottest-volume" {
+	name = "tfsnapshottest-%d"
+	size = 1024000000
+}
+
+resource "purestorage_volume_snapshot" "tfsnapshottest" {
+	volume       = "${purestorage_volume.tfsnapshottest-volume.name}"
+	suffix       = "tfsnap"
+	destroy_mode = "eradicate"
+}`, rInt)
+}
+
+func Test_splitSnapshotName(t *testing.T) {
+	volume, suffix, err := splitSnapshotName("pod::vol.snap1")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if volume != "pod::vol" || suffix != "snap1" {
+		t.Fatalf("Wrong values returned: %s, %s", volume, suffix)
+	}
+
+	for _, name := range []string{"vol", ".snap", "vol."} {
+		if _, _, err := splitSnapshotName(name); err == nil {
+			t.Fatalf("Expected error for %s", name)
+		}
+	}
+}
@@ -19,6 +19,7 @@ package purestorage
 import (
 	"fmt"
 	"math/rand"
+	"regexp"
 	"testing"
 
 	"github.com/devans10/pugo/flasharray"
@@ -52,6 +53,25 @@ func TestAccResourcePureVolume_create(t *testing.T) {
 		},
 	})
 }
+func TestAccResourcePureVolume_createWithUnits(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureVolumeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureVolumeConfigUnits(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureVolumeExists(testAccCheckPureVolumeResourceName, true),
+					resource.TestCheckResourceAttr(testAccCheckPureVolumeResourceName, "size", "1073741824"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccResourcePureVolume_clone(t *testing.T) {
 	rInt := rand.Int()
 
@@ -119,6 +139,209 @@ func TestAccResourcePureVolume_update(t *testing.T) {
 	})
 }
 
+func TestAccResourcePureVolume_truncate(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureVolumeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureVolumeConfigResize(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureVolumeExists(testAccCheckPureVolumeResourceName, true),
+					resource.TestCheckResourceAttr(testAccCheckPureVolumeResourceName, "size", "2048000000"),
+				),
+			},
+			{
+				Config:      testAccCheckPureVolumeConfig(rInt),
+				ExpectError: regexp.MustCompile("unless allow_truncate is set"),
+			},
+			{
+				Config: testAccCheckPureVolumeConfigTruncate(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureVolumeExists(testAccCheckPureVolumeResourceName, true),
+					resource.TestCheckResourceAttr(testAccCheckPureVolumeResourceName, "size", "1024000000"),
+					resource.TestCheckResourceAttrSet(testAccCheckPureVolumeResourceName, "truncate_snapshot"),
+				),
+			},
+		},
+	})
+}
+
+// Set and clear a bandwidth limit
+func TestAccResourcePureVolume_qos(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureVolumeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureVolumeConfigQos(rInt, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureVolumeExists(testAccCheckPureVolumeResourceName, true),
+					resource.TestCheckResourceAttr(testAccCheckPureVolumeResourceName, "bandwidth_limit", "104857600"),
+				),
+			},
+			{
+				Config: testAccCheckPureVolumeConfig(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccCheckPureVolumeResourceName, "bandwidth_limit", ""),
+				),
+			},
+		},
+	})
+}
+
+// Set and clear an IOPS limit.  If the client cannot negotiate the REST API
+// version that supports it, the limit is rejected when the plan is created.
+func TestAccResourcePureVolume_iopsLimit(t *testing.T) {
+	rInt := rand.Int()
+
+	testAccClientPreCheck(t)
+	meta, err := testAccProviderMeta(t)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	steps := []resource.TestStep{
+		{
+			Config: testAccCheckPureVolumeConfigQos(rInt, true),
+			Check: resource.ComposeTestCheckFunc(
+				testAccCheckPureVolumeExists(testAccCheckPureVolumeResourceName, true),
+				resource.TestCheckResourceAttr(testAccCheckPureVolumeResourceName, "iops_limit", "10000"),
+			),
+		},
+		{
+			Config: testAccCheckPureVolumeConfig(rInt),
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr(testAccCheckPureVolumeResourceName, "iops_limit", "0"),
+			),
+		},
+	}
+	if !restVersionAtLeast(meta.(*flasharray.Client).RestVersion, iopsLimitRestVersion) {
+		steps = []resource.TestStep{
+			{
+				Config:      testAccCheckPureVolumeConfigQos(rInt, true),
+				ExpectError: regexp.MustCompile("iops_limit requires REST API version"),
+			},
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureVolumeDestroy,
+		Steps:        steps,
+	})
+}
+
+// Create a volume from the latest snapshot of a protection group
+func TestAccResourcePureVolume_restoreFrom(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureVolumeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureVolumeConfigRestoreFrom(rInt, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureVolumeExists(testAccCheckPureVolumeCloneResourceName, true),
+					resource.TestCheckResourceAttr(testAccCheckPureVolumeCloneResourceName, "restored_snapshot", fmt.Sprintf("tfvolumetest-pg-%d.tfsnap1.tfvolumetest-%d", rInt, rInt)),
+				),
+			},
+			{
+				// A newer snapshot does not copy the volume again.
+				Config: testAccCheckPureVolumeConfigRestoreFrom(rInt, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccCheckPureVolumeCloneResourceName, "restored_snapshot", fmt.Sprintf("tfvolumetest-pg-%d.tfsnap1.tfvolumetest-%d", rInt, rInt)),
+				),
+			},
+		},
+	})
+}
+
+// Add a volume to a protection group
+func TestAccResourcePureVolume_protectionGroups(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureVolumeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureVolumeConfigProtectionGroups(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccCheckPureVolumeResourceName, "protection_groups.#", "1"),
+					testAccCheckPureProtectiongroupVolumes("purestorage_protectiongroup.tfvolumetest", fmt.Sprintf("tfvolumetest-%d", rInt), true),
+				),
+			},
+		},
+	})
+}
+
+// Create a volume that is eradicated when it is destroyed
+func TestAccResourcePureVolume_eradicate(t *testing.T) {
+	rInt := rand.Int()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureVolumeEradicated,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureVolumeConfigEradicate(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureVolumeExists(testAccCheckPureVolumeResourceName, true),
+					resource.TestCheckResourceAttr(testAccCheckPureVolumeResourceName, "destroy_mode", "eradicate"),
+				),
+			},
+		},
+	})
+}
+
+// Destroy a volume outside of Terraform and recover it instead of creating
+// a new one, applying the QoS limit on the recovered volume
+func TestAccResourcePureVolume_recover(t *testing.T) {
+	rInt := rand.Int()
+	var serial string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPureVolumeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPureVolumeConfigRecover(rInt, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureVolumeExists(testAccCheckPureVolumeResourceName, true),
+					testAccCheckPureVolumeSerial(testAccCheckPureVolumeResourceName, &serial),
+				),
+			},
+			{
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*flasharray.Client)
+					if _, err := client.Volumes.DeleteVolume(fmt.Sprintf("tfvolumetest-%d", rInt)); err != nil {
+						t.Fatalf("error destroying volume: %s", err)
+					}
+				},
+				Config: testAccCheckPureVolumeConfigRecover(rInt, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPureVolumeExists(testAccCheckPureVolumeResourceName, true),
+					testAccCheckPureVolumeSerial(testAccCheckPureVolumeResourceName, &serial),
+					resource.TestCheckResourceAttr(testAccCheckPureVolumeResourceName, "bandwidth_limit", "104857600"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckPureVolumeDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(*flasharray.Client)
 
@@ -137,6 +360,23 @@ func testAccCheckPureVolumeDestroy(s *terraform.State) error {
 	return nil
 }
 
+func testAccCheckPureVolumeEradicated(s *terraform.State) error {
+	client := testAccProvider.Meta().(*flasharray.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "purestorage_volume" {
+			continue
+		}
+
+		volumes, _ := client.Volumes.ListVolumes(map[string]string{"pending_only": "true", "names": rs.Primary.ID})
+		if len(volumes) > 0 {
+			return fmt.Errorf("volume '%s' was not eradicated", rs.Primary.ID)
+		}
+	}
+
+	return testAccCheckPureVolumeDestroy(s)
+}
+
 func testAccCheckPureVolumeExists(n string, exists bool) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -160,6 +400,24 @@ func testAccCheckPureVolumeExists(n string, exists bool) resource.TestCheckFunc
 	}
 }
 
+// testAccCheckPureVolumeSerial records the serial of the volume the first
+// time it is called, and checks that it is unchanged after that.
+func testAccCheckPureVolumeSerial(n string, serial *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if *serial == "" {
+			*serial = rs.Primary.Attributes["serial"]
+		} else if rs.Primary.Attributes["serial"] != *serial {
+			return fmt.Errorf("volume serial changed from %s to %s", *serial, rs.Primary.Attributes["serial"])
+		}
+		return nil
+	}
+}
+
 func testAccCheckPureVolumeConfig(rInt int) string {
 	return fmt.Sprintf(`
 resource "purestorage_volume" "tfvolumetest" {
@@ -168,6 +426,28 @@ resource "purestorage_volume" "tfvolumetest" {
 }`, rInt)
 }
 
+func testAccCheckPureVolumeConfigUnits(rInt int) string {
+	return fmt.Sprintf(`
+resource "purestorage_volume" "tfvolumetest" {
+	name = "tfvolumetest-%d"
+	size = "1G"
+}`, rInt)
+}
+
+func testAccCheckPureVolumeConfigQos(rInt int, iops bool) string {
+	iopsLimit := ""
+	if iops {
+		iopsLimit = "iops_limit      = 10000"
+	}
+	return fmt.Sprintf(`
+resource "purestorage_volume" "tfvolumetest" {
+	name            = "tfvolumetest-%d"
+	size            = 1024000000
+	bandwidth_limit = "100M"
+	%s
+}`, rInt, iopsLimit)
+}
+
 func testAccCheckPureVolumeConfigClone(rInt int) string {
 	return fmt.Sprintf(`
 resource "purestorage_volume" "tfvolumetest" {
@@ -196,3 +476,138 @@ resource "purestorage_volume" "tfvolumetest" {
         size = 2048000000
 }`, rInt)
 }
+
+func testAccCheckPureVolumeConfigRestoreFrom(rInt int, newerSnapshot bool) string {
+	config := fmt.Sprintf(`
+resource "purestorage_volume" "tfvolumetest" {
+	name = "tfvolumetest-%d"
+	size = 1024000000
+}
+
+resource "purestorage_protectiongroup" "tfvolumetest" {
+	name         = "tfvolumetest-pg-%d"
+	volumes      = ["${purestorage_volume.tfvolumetest.name}"]
+	destroy_mode = "eradicate"
+}
+
+resource "purestorage_protectiongroup_snapshot" "tfsnap1" {
+	protection_group = "${purestorage_protectiongroup.tfvolumetest.name}"
+	suffix           = "tfsnap1"
+	destroy_mode     = "eradicate"
+}
+
+resource "purestorage_volume" "tfclonevolumetest" {
+	name = "tfvolumetest-restore-%d"
+
+	restore_from {
+		protection_group = "${purestorage_protectiongroup.tfvolumetest.name}"
+		snapshot         = "latest"
+	}
+
+	depends_on = ["purestorage_protectiongroup_snapshot.tfsnap1"]
+}`, rInt, rInt, rInt)
+
+	if newerSnapshot {
+		config += `
+
+resource "purestorage_protectiongroup_snapshot" "tfsnap2" {
+	protection_group = "${purestorage_protectiongroup.tfvolumetest.name}"
+	suffix           = "tfsnap2"
+	destroy_mode     = "eradicate"
+}`
+	}
+	return config
+}
+
+func testAccCheckPureVolumeConfigRecover(rInt int, qos bool) string {
+	bandwidthLimit := ""
+	if qos {
+		bandwidthLimit = `bandwidth_limit      = "100M"`
+	}
+	return fmt.Sprintf(`
+resource "purestorage_volume" "tfvolumetest" {
+	name                 = "tfvolumetest-%d"
+	size                 = 1024000000
+	recover_if_destroyed = true
+	%s
+}`, rInt, bandwidthLimit)
+}
+
+func testAccCheckPureVolumeConfigProtectionGroups(rInt int) string {
+	return fmt.Sprintf(`
+resource "purestorage_protectiongroup" "tfvolumetest" {
+	name = "tfvolumetest-pg-%d"
+
+	lifecycle {
+		ignore_changes = ["volumes"]
+	}
+}
+
+resource "purestorage_volume" "tfvolumetest" {
+	name              = "tfvolumetest-%d"
+	size              = 1024000000
+	protection_groups = ["${purestorage_protectiongroup.tfvolumetest.name}"]
+}`, rInt, rInt)
+}
+
+func testAccCheckPureVolumeConfigEradicate(rInt int) string {
+	return fmt.Sprintf(`
+resource "purestorage_volume" "tfvolumetest" {
+	name         = "tfvolumetest-%d"
+	size         = 1024000000
+	destroy_mode = "eradicate"
+}`, rInt)
+}
+
+func testAccCheckPureVolumeConfigTruncate(rInt int) string {
+	return fmt.Sprintf(`
+resource "purestorage_volume" "tfvolumetest" {
+	name           = "tfvolumetest-%d"
+	size           = 1024000000
+	allow_truncate = true
+}`, rInt)
+}
+
+func Test_splitVolumeName(t *testing.T) {
+	names := map[string][3]string{
+		"vol":             {"", "", "vol"},
+		"vgroup/vol":      {"", "vgroup", "vol"},
+		"pod::vol":        {"pod", "", "vol"},
+		"pod::vgroup/vol": {"", "pod::vgroup", "vol"},
+	}
+	for fullName, expected := range names {
+		pod, vgroup, name := splitVolumeName(fullName)
+		if pod != expected[0] || vgroup != expected[1] || name != expected[2] {
+			t.Fatalf("Wrong values returned for %s: %s, %s, %s", fullName, pod, vgroup, name)
+		}
+		if n := volumeFullName(name, pod, vgroup); n != fullName {
+			t.Fatalf("Wrong value returned: %s", n)
+		}
+	}
+}
+
+func Test_selectRestoreVolumeSnapshot(t *testing.T) {
+	snapshot, err := selectRestoreVolumeSnapshot("array1:pg.1", []string{"array1:pg.1.vol1"}, "")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if snapshot != "array1:pg.1.vol1" {
+		t.Fatalf("Wrong value returned: %s", snapshot)
+	}
+
+	snapshots := []string{"pg.1.vol1", "pg.1.vg/vol2"}
+	snapshot, err = selectRestoreVolumeSnapshot("pg.1", snapshots, "vg/vol2")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if snapshot != "pg.1.vg/vol2" {
+		t.Fatalf("Wrong value returned: %s", snapshot)
+	}
+
+	if _, err := selectRestoreVolumeSnapshot("pg.1", snapshots, ""); err == nil {
+		t.Fatal("Expected error for more than one volume")
+	}
+	if _, err := selectRestoreVolumeSnapshot("pg.1", snapshots, "vol3"); err == nil {
+		t.Fatal("Expected error for a volume that is not in the snapshot")
+	}
+}
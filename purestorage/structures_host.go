@@ -31,3 +31,25 @@ func flattenVolume(in []flasharray.ConnectedVolume) []map[string]interface{} {
 	}
 	return out
 }
+
+// flattenHostConnections splits the connections of a host into its private
+// connections and the connections it shares through its hostgroup.
+func flattenHostConnections(in []flasharray.ConnectedVolume) ([]map[string]interface{}, []map[string]interface{}) {
+	private := make([]map[string]interface{}, 0)
+	shared := make([]map[string]interface{}, 0)
+	for _, v := range in {
+		if v.Hgroup == "" {
+			private = append(private, map[string]interface{}{
+				"vol": v.Vol,
+				"lun": v.Lun,
+			})
+		} else {
+			shared = append(shared, map[string]interface{}{
+				"vol":    v.Vol,
+				"lun":    v.Lun,
+				"hgroup": v.Hgroup,
+			})
+		}
+	}
+	return private, shared
+}
@@ -0,0 +1,79 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"strconv"
+
+	"github.com/devans10/pugo/flasharray"
+)
+
+func flattenPgroupVolumeSnapshots(in []flasharray.Volume) []map[string]interface{} {
+	var out = make([]map[string]interface{}, len(in), len(in))
+	for i, v := range in {
+		m := make(map[string]interface{})
+		m["name"] = v.Name
+		m["source"] = v.Source
+		m["created"] = v.Created
+
+		out[i] = m
+	}
+	return out
+}
+
+func flattenPgroupTargets(in []map[string]interface{}) []map[string]interface{} {
+	var out = make([]map[string]interface{}, len(in), len(in))
+	for i, v := range in {
+		m := make(map[string]interface{})
+		m["name"], _ = v["name"].(string)
+		m["allowed"], _ = v["allowed"].(bool)
+
+		out[i] = m
+	}
+	return out
+}
+
+func flattenPgroupBlackouts(in []map[string]int) []map[string]interface{} {
+	var out = make([]map[string]interface{}, len(in), len(in))
+	for i, v := range in {
+		m := make(map[string]interface{})
+		m["start"] = strconv.Itoa(v["start"])
+		m["end"] = strconv.Itoa(v["end"])
+
+		out[i] = m
+	}
+	return out
+}
+
+func flattenPgroupSnapshots(in []pgroupSnapshot) []map[string]interface{} {
+	var out = make([]map[string]interface{}, len(in), len(in))
+	for i, v := range in {
+		_, suffix, _ := splitSnapshotName(v.Name)
+
+		m := make(map[string]interface{})
+		m["name"] = v.Name
+		m["suffix"] = suffix
+		m["created"] = v.Created
+		m["started"] = v.Started
+		m["completed"] = v.Completed
+		m["progress"] = floatValue(v.Progress)
+		m["data_transferred"] = intValue(v.DataTransferred)
+
+		out[i] = m
+	}
+	return out
+}
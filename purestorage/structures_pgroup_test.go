@@ -0,0 +1,37 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"testing"
+)
+
+func Test_flattenPgroupTargets(t *testing.T) {
+	targets := flattenPgroupTargets([]map[string]interface{}{
+		{"name": "array2", "allowed": true},
+		{"name": "nfs1"},
+	})
+	if len(targets) != 2 {
+		t.Fatalf("Wrong number of targets: %d", len(targets))
+	}
+	if targets[0]["name"] != "array2" || targets[0]["allowed"] != true {
+		t.Fatalf("Wrong target returned: %v", targets[0])
+	}
+	if targets[1]["name"] != "nfs1" || targets[1]["allowed"] != false {
+		t.Fatalf("Wrong target returned: %v", targets[1])
+	}
+}
@@ -0,0 +1,43 @@
+/*
+   Copyright 2018 David Evans
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package purestorage
+
+import (
+	"github.com/devans10/pugo/flasharray"
+)
+
+func flattenVolumePerformance(in []flasharray.Volume) []map[string]interface{} {
+	var out = make([]map[string]interface{}, len(in), len(in))
+	for i, v := range in {
+		m := make(map[string]interface{})
+		m["reads_per_sec"] = intValue(v.ReadsPerSec)
+		m["writes_per_sec"] = intValue(v.WritesPerSec)
+		m["input_per_sec"] = intValue(v.InputPerSec)
+		m["output_per_sec"] = intValue(v.OutputPerSec)
+		m["usec_per_read_op"] = intValue(v.UsecPerReadOp)
+		m["usec_per_write_op"] = intValue(v.UsecPerWriteOp)
+		m["san_usec_per_read_op"] = intValue(v.SanUsecPerReadOp)
+		m["san_usec_per_write_op"] = intValue(v.SanUsecPerWriteOp)
+		m["bytes_per_read"] = intValue(v.BytesPerRead)
+		m["bytes_per_write"] = intValue(v.BytesPerWrite)
+		m["bytes_per_op"] = intValue(v.BytesPerOp)
+		m["time"] = v.Time
+
+		out[i] = m
+	}
+	return out
+}